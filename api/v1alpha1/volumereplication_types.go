@@ -52,9 +52,11 @@ const (
 
 // VolumeReplicationSpec defines the desired state of VolumeReplication.
 type VolumeReplicationSpec struct {
-	// VolumeReplicationClass is the VolumeReplicationClass name for this VolumeReplication resource
-	// +kubebuilder:validation:Required
-	VolumeReplicationClass string `json:"volumeReplicationClass"`
+	// VolumeReplicationClass is the VolumeReplicationClass name for this VolumeReplication resource.
+	// If empty, the class is chosen from the CSI driver and the StorageClass
+	// of the claim
+	// +kubebuilder:validation:Optional
+	VolumeReplicationClass string `json:"volumeReplicationClass,omitempty"`
 
 	// ReplicationState represents the replication operation to be performed on the volume.
 	// Supported operations are "primary", "secondary" and "resync"
@@ -72,12 +74,185 @@ type VolumeReplicationSpec struct {
 	// replicationHandle represents an existing (but new) replication id
 	// +kubebuilder:validation:Optional
 	ReplicationHandle string `json:"replicationHandle"`
+
+	// Paused suspends the reconciliation of the VolumeReplication, no
+	// replication operations are issued to the driver while it is set,
+	// including the ones disabling replication on deletion
+	// +kubebuilder:validation:Optional
+	Paused bool `json:"paused,omitempty"`
+
+	// RestartWorkloads restarts the Deployments and StatefulSets using the
+	// volume once it is promoted from secondary, and scales up the ones
+	// annotated as DR standbys
+	// +kubebuilder:validation:Optional
+	RestartWorkloads bool `json:"restartWorkloads,omitempty"`
+
+	// DependsOn are the VolumeReplications in the same namespace that are
+	// promoted before this one and demoted after it
+	// +kubebuilder:validation:Optional
+	DependsOn []corev1.LocalObjectReference `json:"dependsOn,omitempty"`
+}
+
+// HookType is the point of the transition a hook is run at.
+type HookType string
+
+const (
+	// PreDemoteHook is run before the volume is demoted.
+	PreDemoteHook HookType = "PreDemote"
+
+	// PostPromoteHook is run after the volume is promoted.
+	PostPromoteHook HookType = "PostPromote"
+)
+
+// HookPhase is the phase of a hook run.
+type HookPhase string
+
+const (
+	// HookRunning is the phase of a hook that has not completed yet.
+	HookRunning HookPhase = "Running"
+
+	// HookSucceeded is the phase of a hook that succeeded.
+	HookSucceeded HookPhase = "Succeeded"
+
+	// HookFailed is the phase of a hook that failed or timed out.
+	HookFailed HookPhase = "Failed"
+)
+
+// HookStatus reports the outcome of the last run of a hook.
+type HookStatus struct {
+	// Name is the name of the hook
+	Name string `json:"name"`
+	// Type is the point of the transition the hook was run at
+	Type HookType `json:"type"`
+	// Phase is the phase of the hook run
+	Phase HookPhase `json:"phase"`
+	// Message describes the outcome of the hook run
+	// +optional
+	Message string `json:"message,omitempty"`
+	// ObservedGeneration is the generation the hook was run for
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// StartTime is the time the hook was started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time the hook completed
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// VolumeReplicationMember describes a single volume covered by the VolumeReplication.
+type VolumeReplicationMember struct {
+	// PVCName is the name of the member PersistentVolumeClaim
+	PVCName string `json:"pvcName"`
+	// VolumeHandle is the CSI volume handle of the PersistentVolume bound to the claim
+	// +optional
+	VolumeHandle string `json:"volumeHandle,omitempty"`
+	// Bound is true when the claim is bound to a PersistentVolume
+	Bound bool `json:"bound"`
+}
+
+// EnabledReplication records the inputs the replication was last enabled with.
+type EnabledReplication struct {
+	// InputsHash is the hash of the VolumeReplicationClass parameters passed
+	// to the driver, the secret reference, the replication source and the
+	// replication handle the replication was enabled with
+	InputsHash string `json:"inputsHash"`
+	// SecretResourceVersion is the resourceVersion of the replication secret
+	// the replication was enabled with
+	// +optional
+	SecretResourceVersion string `json:"secretResourceVersion,omitempty"`
+	// LastEnableTime is the time the replication was last enabled
+	// +optional
+	LastEnableTime *metav1.Time `json:"lastEnableTime,omitempty"`
+}
+
+// DryRunRPC describes a replication RPC the operator would have issued.
+type DryRunRPC struct {
+	// Operation is the name of the RPC
+	Operation string `json:"operation"`
+	// Source is the replication source, e.g. "volume:<volume handle>"
+	Source string `json:"source"`
+	// ReplicationID is the replication id passed to the RPC
+	// +optional
+	ReplicationID string `json:"replicationID,omitempty"`
+	// Force is the force flag passed to the RPC
+	// +optional
+	Force bool `json:"force,omitempty"`
+	// Parameters are the parameters passed to the RPC
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+	// SecretKeys are the keys of the secrets passed to the RPC, the
+	// values are never reported
+	// +optional
+	SecretKeys []string `json:"secretKeys,omitempty"`
+}
+
+// DryRunStatus reports the outcome of a dry-run reconciliation.
+type DryRunStatus struct {
+	// Time is the time of the dry-run reconciliation
+	Time *metav1.Time `json:"time,omitempty"`
+	// ObservedGeneration is the generation the dry-run was done for
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// RPCs are the replication RPCs the operator would have issued
+	// +optional
+	RPCs []DryRunRPC `json:"rpcs,omitempty"`
+	// Message is the message the reconciliation would have reported
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// WorkloadAction is the action taken on a workload after a promotion.
+type WorkloadAction string
+
+const (
+	// WorkloadRestarted is the action of a workload that was restarted.
+	WorkloadRestarted WorkloadAction = "Restarted"
+
+	// WorkloadScaledUp is the action of a DR standby workload that was
+	// scaled up.
+	WorkloadScaledUp WorkloadAction = "ScaledUp"
+
+	// WorkloadFailed is the action of a workload that could not be
+	// restarted or scaled up.
+	WorkloadFailed WorkloadAction = "Failed"
+)
+
+// TouchedWorkload describes a workload touched after a promotion.
+type TouchedWorkload struct {
+	// Kind is the kind of the workload, Deployment or StatefulSet
+	Kind string `json:"kind"`
+	// Name is the name of the workload
+	Name string `json:"name"`
+	// Action is the action taken on the workload
+	Action WorkloadAction `json:"action"`
+	// Message describes the action
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// WorkloadRestartStatus reports the workloads touched after the last
+// promotion.
+type WorkloadRestartStatus struct {
+	// ObservedGeneration is the generation of the promotion
+	ObservedGeneration int64 `json:"observedGeneration"`
+	// Time is the time the workloads were touched, it is unset while the
+	// restart is pending
+	// +optional
+	Time *metav1.Time `json:"time,omitempty"`
+	// Workloads are the workloads touched
+	// +optional
+	Workloads []TouchedWorkload `json:"workloads,omitempty"`
 }
 
 // VolumeReplicationStatus defines the observed state of VolumeReplication.
 type VolumeReplicationStatus struct {
 	State   State  `json:"state,omitempty"`
 	Message string `json:"message,omitempty"`
+	// VolumeReplicationClass is the class the VolumeReplication is
+	// reconciled with, it is chosen once when the spec sets none
+	// +optional
+	VolumeReplicationClass string `json:"volumeReplicationClass,omitempty"`
 	// Conditions are the list of conditions and their status.
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 	// observedGeneration is the last generation change the operator has dealt with
@@ -85,6 +260,25 @@ type VolumeReplicationStatus struct {
 	ObservedGeneration int64        `json:"observedGeneration,omitempty"`
 	LastStartTime      *metav1.Time `json:"lastStartTime,omitempty"`
 	LastCompletionTime *metav1.Time `json:"lastCompletionTime,omitempty"`
+	// Members are the volumes covered by the VolumeReplication when the
+	// dataSource is a VolumeGroup
+	// +optional
+	Members []VolumeReplicationMember `json:"members,omitempty"`
+	// Enabled records the inputs the replication was last enabled with.
+	// Replication is only enabled again when these change
+	// +optional
+	Enabled *EnabledReplication `json:"enabled,omitempty"`
+	// DryRun reports the last dry-run reconciliation, it is only set while
+	// the operator or the VolumeReplication is in dry-run mode
+	// +optional
+	DryRun *DryRunStatus `json:"dryRun,omitempty"`
+	// Hooks report the outcome of the last run of the transition hooks
+	// +optional
+	Hooks []HookStatus `json:"hooks,omitempty"`
+	// WorkloadRestart reports the workloads touched after the last
+	// promotion from secondary
+	// +optional
+	WorkloadRestart *WorkloadRestartStatus `json:"workloadRestart,omitempty"`
 }
 
 // +kubebuilder:object:root=true
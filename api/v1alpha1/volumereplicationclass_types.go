@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	batchv1 "k8s.io/api/batch/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -31,6 +32,100 @@ type VolumeReplicationClassSpec struct {
 	// creating volume replicas
 	// +kubebuilder:validation:Optional
 	Parameters map[string]string `json:"parameters,omitempty"`
+	// Paused suspends the reconciliation of all the VolumeReplications
+	// using this class, including their deletion
+	// +kubebuilder:validation:Optional
+	Paused bool `json:"paused,omitempty"`
+	// AllowedNamespaces restricts the namespaces whose VolumeReplications
+	// may use this class, all namespaces are allowed if unset
+	// +kubebuilder:validation:Optional
+	AllowedNamespaces *AllowedNamespaces `json:"allowedNamespaces,omitempty"`
+	// MaxVolumeReplicationsPerNamespace limits the number of
+	// VolumeReplications using this class in each namespace, there is no
+	// limit if unset
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	MaxVolumeReplicationsPerNamespace *int32 `json:"maxVolumeReplicationsPerNamespace,omitempty"`
+	// Hooks are run around the transitions of the volumes of the
+	// VolumeReplications using this class, e.g. to quiesce the applications
+	// using them
+	// +kubebuilder:validation:Optional
+	Hooks *TransitionHooks `json:"hooks,omitempty"`
+}
+
+// AllowedNamespaces selects namespaces by name or by label. A namespace is
+// allowed if it is listed in Names or matches the Selector.
+type AllowedNamespaces struct {
+	// Names are the names of the allowed namespaces
+	// +kubebuilder:validation:Optional
+	Names []string `json:"names,omitempty"`
+	// Selector selects the allowed namespaces by label
+	// +kubebuilder:validation:Optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// TransitionHooks are the hooks run around the transitions of the volume.
+type TransitionHooks struct {
+	// PreDemote hooks are run in order before the volume is demoted
+	// +optional
+	PreDemote []TransitionHook `json:"preDemote,omitempty"`
+	// PostPromote hooks are run in order after the volume is promoted
+	// +optional
+	PostPromote []TransitionHook `json:"postPromote,omitempty"`
+}
+
+// HookFailurePolicy defines how the failure of a hook is handled.
+// +kubebuilder:validation:Enum=Fail;Ignore
+type HookFailurePolicy string
+
+const (
+	// HookFailurePolicyFail stops the transition when the hook fails.
+	HookFailurePolicyFail HookFailurePolicy = "Fail"
+
+	// HookFailurePolicyIgnore continues the transition when the hook fails.
+	HookFailurePolicyIgnore HookFailurePolicy = "Ignore"
+)
+
+// TransitionHook is a command run in pods or a Job run around a transition
+// of the volume. Exactly one of Exec and Job must be set.
+type TransitionHook struct {
+	// Name identifies the hook in the status
+	Name string `json:"name"`
+	// Exec runs a command in the selected pods
+	// +optional
+	Exec *ExecHook `json:"exec,omitempty"`
+	// Job runs a Job created from the template
+	// +optional
+	Job *JobHook `json:"job,omitempty"`
+	// Timeout is the time the hook is allowed to run, defaults to 5m
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// FailurePolicy defines whether the transition stops or continues when
+	// the hook fails, defaults to Fail
+	// +optional
+	FailurePolicy HookFailurePolicy `json:"failurePolicy,omitempty"`
+}
+
+// ExecHook runs a command in the running pods selected by label in the
+// namespace of the VolumeReplication.
+type ExecHook struct {
+	// Selector selects the pods the command is run in
+	Selector metav1.LabelSelector `json:"selector"`
+	// Container is the container the command is run in, defaults to the
+	// first container of the pod
+	// +optional
+	Container string `json:"container,omitempty"`
+	// Command is the command to run, it must exit with zero on success
+	// +kubebuilder:validation:MinItems=1
+	Command []string `json:"command"`
+}
+
+// JobHook runs a Job in the namespace of the VolumeReplication.
+type JobHook struct {
+	// Template is the template of the Job
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	Template batchv1.JobTemplateSpec `json:"template"`
 }
 
 // VolumeReplicationClassStatus defines the observed state of VolumeReplicationClass.
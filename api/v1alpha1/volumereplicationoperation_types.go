@@ -0,0 +1,176 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OperationPhase is the phase of a VolumeReplicationOperation.
+type OperationPhase string
+
+const (
+	// OperationRunning is the phase while transitions are started or in
+	// progress.
+	OperationRunning OperationPhase = "Running"
+	// OperationSuspended is the phase while no further transitions are
+	// started.
+	OperationSuspended OperationPhase = "Suspended"
+	// OperationSucceeded is the phase once all the transitions succeeded.
+	OperationSucceeded OperationPhase = "Succeeded"
+	// OperationFailed is the phase once a transition failed and no further
+	// transitions are started.
+	OperationFailed OperationPhase = "Failed"
+)
+
+// TargetPhase is the phase of the transition of a single VolumeReplication.
+type TargetPhase string
+
+const (
+	// TargetPending is the phase until the transition is started.
+	TargetPending TargetPhase = "Pending"
+	// TargetInProgress is the phase while the transition is in progress.
+	TargetInProgress TargetPhase = "InProgress"
+	// TargetSucceeded is the phase once the transition completed.
+	TargetSucceeded TargetPhase = "Succeeded"
+	// TargetFailed is the phase once the transition failed or timed out.
+	TargetFailed TargetPhase = "Failed"
+)
+
+// VolumeReplicationOperationSpec defines the replication state applied to a
+// set of VolumeReplications.
+type VolumeReplicationOperationSpec struct {
+	// Namespaces are the namespaces of the selected VolumeReplications, all
+	// namespaces are selected when empty
+	// +kubebuilder:validation:Optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Selector selects the VolumeReplications by label, all the
+	// VolumeReplications of the namespaces are selected when unset
+	// +kubebuilder:validation:Optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// ReplicationState is the replication state applied to the selected
+	// VolumeReplications
+	// +kubebuilder:validation:Required
+	ReplicationState ReplicationState `json:"replicationState"`
+
+	// MaxConcurrent is the number of VolumeReplications transitioning at the
+	// same time
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=10
+	MaxConcurrent int32 `json:"maxConcurrent,omitempty"`
+
+	// MaxFailures is the number of failed transitions tolerated, no further
+	// transitions are started once it is exceeded. Failures are not limited
+	// when unset
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	MaxFailures *int32 `json:"maxFailures,omitempty"`
+
+	// Timeout is the time a VolumeReplication has to complete its transition
+	// before it is counted as failed, defaults to 10 minutes
+	// +kubebuilder:validation:Optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// Suspend stops starting further transitions, the transitions in
+	// progress are still tracked. Clearing it resumes the operation
+	// +kubebuilder:validation:Optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// OperationTarget reports the transition of a single VolumeReplication.
+type OperationTarget struct {
+	// Namespace is the namespace of the VolumeReplication
+	Namespace string `json:"namespace"`
+	// Name is the name of the VolumeReplication
+	Name string `json:"name"`
+	// Phase is the phase of the transition
+	Phase TargetPhase `json:"phase"`
+	// Message describes the failure of the transition
+	// +optional
+	Message string `json:"message,omitempty"`
+	// StartTime is the time the transition was started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time the transition succeeded or failed
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// VolumeReplicationOperationStatus defines the observed state of
+// VolumeReplicationOperation.
+type VolumeReplicationOperationStatus struct {
+	// Phase is the phase of the operation
+	// +optional
+	Phase OperationPhase `json:"phase,omitempty"`
+	// Message describes the phase of the operation
+	// +optional
+	Message string `json:"message,omitempty"`
+	// StartTime is the time the VolumeReplications were selected
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time the operation succeeded or failed
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Total is the number of selected VolumeReplications
+	Total int32 `json:"total,omitempty"`
+	// InProgress is the number of transitions in progress
+	InProgress int32 `json:"inProgress,omitempty"`
+	// Succeeded is the number of transitions that succeeded
+	Succeeded int32 `json:"succeeded,omitempty"`
+	// Failed is the number of transitions that failed
+	Failed int32 `json:"failed,omitempty"`
+	// Targets report the transition of each selected VolumeReplication
+	// +optional
+	Targets []OperationTarget `json:"targets,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,shortName=vro
+// +kubebuilder:printcolumn:JSONPath=".spec.replicationState",name=desiredState,type=string
+// +kubebuilder:printcolumn:JSONPath=".status.phase",name=phase,type=string
+// +kubebuilder:printcolumn:JSONPath=".status.succeeded",name=succeeded,type=integer
+// +kubebuilder:printcolumn:JSONPath=".status.failed",name=failed,type=integer
+// +kubebuilder:printcolumn:JSONPath=".status.total",name=total,type=integer
+// +kubebuilder:printcolumn:JSONPath=".metadata.creationTimestamp",name=Age,type=date
+
+// VolumeReplicationOperation is the Schema for the volumereplicationoperations
+// API. It applies a replication state to a set of VolumeReplications.
+type VolumeReplicationOperation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VolumeReplicationOperationSpec   `json:"spec,omitempty"`
+	Status VolumeReplicationOperationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VolumeReplicationOperationList contains a list of VolumeReplicationOperation.
+type VolumeReplicationOperationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []VolumeReplicationOperation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VolumeReplicationOperation{}, &VolumeReplicationOperationList{})
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VolumeReplicationPolicySpec defines the replication of the claims selected
+// by the policy.
+type VolumeReplicationPolicySpec struct {
+	// Selector selects the claims of the namespace replicated by the policy
+	// +kubebuilder:validation:Required
+	Selector metav1.LabelSelector `json:"selector"`
+
+	// VolumeReplicationClass is the VolumeReplicationClass of the
+	// VolumeReplications of the policy
+	// +kubebuilder:validation:Required
+	VolumeReplicationClass string `json:"volumeReplicationClass"`
+
+	// ReplicationState is the replication state of the VolumeReplications of
+	// the policy
+	// +kubebuilder:validation:Required
+	ReplicationState ReplicationState `json:"replicationState"`
+
+	// AutoResync represents the volumes to be auto resynced when
+	// ReplicationState is "secondary"
+	// +kubebuilder:validation:Optional
+	AutoResync bool `json:"autoResync,omitempty"`
+}
+
+// PolicyMember reports the replication of a claim selected by the policy.
+type PolicyMember struct {
+	// PVCName is the name of the claim
+	PVCName string `json:"pvcName"`
+	// State is the replication state of the VolumeReplication of the claim
+	// +optional
+	State State `json:"state,omitempty"`
+	// Completed is true once the VolumeReplication completed the transition
+	// to the replication state of the policy
+	Completed bool `json:"completed"`
+	// Message describes why the transition is not completed
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// VolumeReplicationPolicyStatus defines the observed state of
+// VolumeReplicationPolicy.
+type VolumeReplicationPolicyStatus struct {
+	// ObservedGeneration is the last generation of the policy applied to its
+	// VolumeReplications
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Total is the number of claims selected by the policy
+	Total int32 `json:"total,omitempty"`
+	// Completed is the number of VolumeReplications that completed the
+	// transition to the replication state of the policy
+	Completed int32 `json:"completed,omitempty"`
+	// Members report the replication of each claim selected by the policy
+	// +optional
+	Members []PolicyMember `json:"members,omitempty"`
+	// Conditions are the list of conditions and their status.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=vrp
+// +kubebuilder:printcolumn:JSONPath=".spec.volumeReplicationClass",name=volumeReplicationClass,type=string
+// +kubebuilder:printcolumn:JSONPath=".spec.replicationState",name=desiredState,type=string
+// +kubebuilder:printcolumn:JSONPath=".status.completed",name=completed,type=integer
+// +kubebuilder:printcolumn:JSONPath=".status.total",name=total,type=integer
+// +kubebuilder:printcolumn:JSONPath=".metadata.creationTimestamp",name=Age,type=date
+
+// VolumeReplicationPolicy is the Schema for the volumereplicationpolicies
+// API. It keeps a VolumeReplication for each claim it selects.
+type VolumeReplicationPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VolumeReplicationPolicySpec   `json:"spec,omitempty"`
+	Status VolumeReplicationPolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VolumeReplicationPolicyList contains a list of VolumeReplicationPolicy.
+type VolumeReplicationPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []VolumeReplicationPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VolumeReplicationPolicy{}, &VolumeReplicationPolicyList{})
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// mock-replication-driver serves a CSI-Addons replication driver without any
+// storage backend on a unix socket, to test the operator end-to-end.
+package main
+
+import (
+	"flag"
+	"os"
+	"strings"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/csi-addons/volume-replication-operator/pkg/client/fake"
+	"github.com/csi-addons/volume-replication-operator/pkg/mockdriver"
+)
+
+// faultsFlag collects the repeated --fault flags.
+type faultsFlag []fake.Fault
+
+func (f *faultsFlag) String() string {
+	return ""
+}
+
+func (f *faultsFlag) Set(value string) error {
+	fault, err := mockdriver.ParseFault(value)
+	if err != nil {
+		return err
+	}
+
+	*f = append(*f, fault)
+
+	return nil
+}
+
+func main() {
+	var (
+		endpoint   string
+		splitBrain string
+		faults     faultsFlag
+		opts       zap.Options
+	)
+
+	driverOpts := mockdriver.Options{}
+
+	flag.StringVar(&endpoint, "csi-address", "unix:///run/csi/socket", "The unix socket to serve the driver on.")
+	flag.StringVar(&driverOpts.Name, "driver-name", "mock.replication.storage.io", "The CSI driver name.")
+	flag.StringVar(&driverOpts.Version, "driver-version", "v0.0.1", "The CSI driver version.")
+	flag.StringVar(&driverOpts.StateFile, "state-file", "",
+		"The JSON file to keep the replication state in. The state is only kept in memory if empty.")
+	flag.DurationVar(&driverOpts.Latency, "latency", 0, "The latency added to every replication RPC.")
+	flag.IntVar(&driverOpts.ReadyAfter, "ready-after", 1, "The number of ResyncVolume calls after which a resync completes.")
+	flag.StringVar(&splitBrain, "split-brain", "",
+		"Comma separated volume and volume group ids that end up in split brain when demoted from primary.")
+	flag.Var(&faults, "fault",
+		"A fault to inject as <operation>:<code>[:<times>], e.g. PromoteVolume:FailedPrecondition:1. Can be repeated.")
+	opts.BindFlags(flag.CommandLine)
+	flag.Parse()
+
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	log := ctrl.Log.WithName("mock-replication-driver")
+
+	if splitBrain != "" {
+		driverOpts.SplitBrain = strings.Split(splitBrain, ",")
+	}
+
+	driverOpts.Faults = faults
+	driverOpts.Log = log
+
+	driver, err := mockdriver.New(driverOpts)
+	if err != nil {
+		log.Error(err, "failed to create driver")
+		os.Exit(1)
+	}
+
+	err = driver.Serve(ctrl.SetupSignalHandler(), endpoint)
+	if err != nil {
+		log.Error(err, "failed to serve driver")
+		os.Exit(1)
+	}
+}
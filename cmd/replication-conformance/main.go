@@ -0,0 +1,176 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// replication-conformance runs the replication conformance checks against a
+// CSI-Addons replication driver.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	replicationlib "github.com/csi-addons/spec/lib/go/replication"
+
+	grpcClient "github.com/csi-addons/volume-replication-operator/pkg/client"
+	"github.com/csi-addons/volume-replication-operator/pkg/conformance"
+)
+
+// options are the command line options.
+type options struct {
+	endpoint      string
+	rpcTimeout    time.Duration
+	volumeID      string
+	volumeGroupID string
+	parameters    string
+	secretsFile   string
+	junitReport   string
+	suiteName     string
+	cfg           conformance.Config
+}
+
+func main() {
+	opts := options{}
+
+	flag.StringVar(&opts.endpoint, "csi-address", "unix:///run/csi/socket", "Address of the CSI driver socket.")
+	flag.DurationVar(&opts.rpcTimeout, "rpc-timeout", time.Minute, "The timeout for RPCs to the CSI driver.")
+	flag.StringVar(&opts.volumeID, "volume-id", "", "The existing volume to run the checks against.")
+	flag.StringVar(&opts.volumeGroupID, "volume-group-id", "", "The existing volume group to run the checks against.")
+	flag.StringVar(&opts.cfg.ReplicationID, "replication-id", "", "The replication id passed to the RPCs.")
+	flag.StringVar(&opts.parameters, "parameters", "", "Comma separated key=value parameters passed to the RPCs.")
+	flag.StringVar(&opts.secretsFile, "secrets-file", "", "A JSON file with the secrets passed to the RPCs.")
+	flag.IntVar(&opts.cfg.ResyncPolls, "resync-polls", 10, "The maximum number of ResyncVolume calls to wait for a volume to be ready.")
+	flag.DurationVar(&opts.cfg.ResyncInterval, "resync-interval", 5*time.Second, "The interval between ResyncVolume calls.")
+	flag.StringVar(&opts.junitReport, "junit-report", "", "The file to write the JUnit report to.")
+	flag.StringVar(&opts.suiteName, "suite-name", "replication-conformance", "The name of the suite in the JUnit report.")
+	flag.Parse()
+
+	err := run(opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(opts options) error {
+	cfg := opts.cfg
+
+	switch {
+	case opts.volumeID != "" && opts.volumeGroupID != "":
+		return errors.New("only one of volume-id and volume-group-id can be set")
+	case opts.volumeID != "":
+		cfg.ReplicationSource = &replicationlib.ReplicationSource{
+			Type: &replicationlib.ReplicationSource_Volume{
+				Volume: &replicationlib.ReplicationSource_VolumeSource{VolumeId: opts.volumeID},
+			},
+		}
+	case opts.volumeGroupID != "":
+		cfg.ReplicationSource = &replicationlib.ReplicationSource{
+			Type: &replicationlib.ReplicationSource_Volumegroup{
+				Volumegroup: &replicationlib.ReplicationSource_VolumeGroupSource{VolumeGroupId: opts.volumeGroupID},
+			},
+		}
+	default:
+		return errors.New("one of volume-id and volume-group-id is required")
+	}
+
+	var err error
+
+	cfg.Parameters, err = parseParameters(opts.parameters)
+	if err != nil {
+		return err
+	}
+
+	if opts.secretsFile != "" {
+		data, rErr := os.ReadFile(opts.secretsFile)
+		if rErr != nil {
+			return fmt.Errorf("failed to read secrets: %w", rErr)
+		}
+
+		err = json.Unmarshal(data, &cfg.Secrets)
+		if err != nil {
+			return fmt.Errorf("failed to parse secrets: %w", err)
+		}
+	}
+
+	client, err := grpcClient.New(opts.endpoint, opts.rpcTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %q: %w", opts.endpoint, err)
+	}
+
+	err = client.Probe()
+	if err != nil {
+		return fmt.Errorf("failed to probe the driver: %w", err)
+	}
+
+	report := conformance.Run(grpcClient.NewReplicationClient(client.Client, opts.rpcTimeout), cfg)
+
+	for _, result := range report.Results {
+		switch {
+		case result.Failure != "":
+			fmt.Printf("FAIL %s: %s\n", result.Name, result.Failure)
+		case result.Skipped != "":
+			fmt.Printf("SKIP %s: %s\n", result.Name, result.Skipped)
+		default:
+			fmt.Printf("PASS %s (%s)\n", result.Name, result.Duration.Round(time.Millisecond))
+		}
+	}
+
+	if opts.junitReport != "" {
+		f, cErr := os.Create(opts.junitReport)
+		if cErr != nil {
+			return fmt.Errorf("failed to create the JUnit report: %w", cErr)
+		}
+
+		err = report.WriteJUnit(f, opts.suiteName)
+		if cErr = f.Close(); err == nil {
+			err = cErr
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to write the JUnit report: %w", err)
+		}
+	}
+
+	if report.Failed() {
+		return errors.New("conformance checks failed")
+	}
+
+	return nil
+}
+
+// parseParameters parses comma separated key=value pairs.
+func parseParameters(value string) (map[string]string, error) {
+	parameters := map[string]string{}
+	if value == "" {
+		return parameters, nil
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("parameter %q is not in the key=value format", pair)
+		}
+
+		parameters[k] = v
+	}
+
+	return parameters, nil
+}
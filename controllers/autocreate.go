@@ -0,0 +1,344 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// volumeReplicationClassAnnotation requests a VolumeReplication with the
+	// given class for a claim, or for all the claims of a StorageClass.
+	volumeReplicationClassAnnotation = replicationParameterPrefix + "volume-replication-class"
+	// replicationStateAnnotation sets the replication state of the
+	// VolumeReplications created in a namespace.
+	replicationStateAnnotation = replicationParameterPrefix + "replication-state"
+
+	// pvcStorageClassIndex indexes claims by the name of their StorageClass.
+	pvcStorageClassIndex = "spec.storageClassName"
+)
+
+// autoCreateReconciler creates a VolumeReplication for the claims annotated
+// with a VolumeReplicationClass, directly or through their StorageClass, and
+// deletes it once the annotation is removed. It only handles the classes of
+// the driver of the operator.
+type autoCreateReconciler struct {
+	client.Client
+
+	scheme       *runtime.Scheme
+	log          logr.Logger
+	recorder     record.EventRecorder
+	driverName   string
+	defaultState replicationv1alpha1.ReplicationState
+}
+
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+// Reconcile creates, updates or deletes the VolumeReplication of the claim.
+func (r *autoCreateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.log.WithValues("Request.Name", req.Name, "Request.Namespace", req.Namespace)
+
+	pvc := &corev1.PersistentVolumeClaim{}
+
+	err := r.Get(ctx, req.NamespacedName, pvc)
+	if err != nil {
+		// the VolumeReplication of a deleted claim is garbage collected
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	vr := &replicationv1alpha1.VolumeReplication{}
+
+	err = r.Get(ctx, req.NamespacedName, vr)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+
+	exists := err == nil
+
+	if !pvc.GetDeletionTimestamp().IsZero() {
+		// the VolumeReplication protects the claim with a finalizer, it is
+		// not garbage collected while the claim exists
+		if !exists || !metav1.IsControlledBy(vr, pvc) {
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, r.deleteVolumeReplication(ctx, logger, pvc, vr, "deleted VolumeReplication as the claim is being deleted")
+	}
+
+	className, err := r.getAutoReplicationClass(ctx, pvc)
+	if err != nil {
+		logger.Error(err, "failed to get the volumeReplicationClass of the claim")
+
+		return ctrl.Result{}, err
+	}
+
+	if exists && !metav1.IsControlledBy(vr, pvc) {
+		if className != "" {
+			logger.Info("volumeReplication is not owned by the claim, skipping", "VRName", vr.Name)
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	if className != "" {
+		owned, err := isDriverClass(ctx, r, r.driverName, className)
+		if err != nil || !owned {
+			return ctrl.Result{}, err
+		}
+	}
+
+	switch {
+	case className == "" && exists:
+		return ctrl.Result{}, r.deleteVolumeReplication(ctx, logger, pvc, vr, "deleted VolumeReplication as replication is no longer requested")
+	case className == "":
+		return ctrl.Result{}, nil
+	case exists && vr.Spec.VolumeReplicationClass != className:
+		logger.Info("updating volumeReplicationClass of volumeReplication", "VRName", vr.Name, "VRCName", className)
+
+		patch := client.MergeFrom(vr.DeepCopy())
+		vr.Spec.VolumeReplicationClass = className
+
+		return ctrl.Result{}, r.Patch(ctx, vr, patch)
+	case exists:
+		return ctrl.Result{}, nil
+	}
+
+	return ctrl.Result{}, r.createVolumeReplication(ctx, logger, pvc, className)
+}
+
+// getAutoReplicationClass returns the VolumeReplicationClass requested for
+// the claim, the annotation of the claim takes precedence over the one of its
+// StorageClass.
+func (r *autoCreateReconciler) getAutoReplicationClass(ctx context.Context, pvc *corev1.PersistentVolumeClaim) (string, error) {
+	if className, ok := pvc.Annotations[volumeReplicationClassAnnotation]; ok {
+		return className, nil
+	}
+
+	if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
+		return "", nil
+	}
+
+	sc := &storagev1.StorageClass{}
+
+	err := r.Get(ctx, types.NamespacedName{Name: *pvc.Spec.StorageClassName}, sc)
+	if err != nil {
+		return "", client.IgnoreNotFound(err)
+	}
+
+	return sc.Annotations[volumeReplicationClassAnnotation], nil
+}
+
+// getInitialReplicationState returns the replication state of the namespace,
+// or the default one if the namespace sets none.
+func (r *autoCreateReconciler) getInitialReplicationState(
+	ctx context.Context,
+	logger logr.Logger,
+	namespace string,
+) (replicationv1alpha1.ReplicationState, error) {
+	ns := &corev1.Namespace{}
+
+	err := r.Get(ctx, types.NamespacedName{Name: namespace}, ns)
+	if err != nil {
+		return "", err
+	}
+
+	state, ok := ns.Annotations[replicationStateAnnotation]
+	if !ok {
+		return r.defaultState, nil
+	}
+
+	switch replicationv1alpha1.ReplicationState(state) {
+	case replicationv1alpha1.Primary, replicationv1alpha1.Secondary:
+		return replicationv1alpha1.ReplicationState(state), nil
+	default:
+		logger.Info("ignoring invalid replication state of namespace", "Namespace", namespace, "ReplicationState", state)
+
+		return r.defaultState, nil
+	}
+}
+
+// createVolumeReplication creates the VolumeReplication of the claim, named
+// after the claim and controlled by it.
+func (r *autoCreateReconciler) createVolumeReplication(
+	ctx context.Context,
+	logger logr.Logger,
+	pvc *corev1.PersistentVolumeClaim,
+	className string,
+) error {
+	state, err := r.getInitialReplicationState(ctx, logger, pvc.Namespace)
+	if err != nil {
+		logger.Error(err, "failed to get the replication state of the namespace")
+
+		return err
+	}
+
+	vr := &replicationv1alpha1.VolumeReplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pvc.Name,
+			Namespace: pvc.Namespace,
+		},
+		Spec: replicationv1alpha1.VolumeReplicationSpec{
+			VolumeReplicationClass: className,
+			ReplicationState:       state,
+			DataSource: corev1.TypedLocalObjectReference{
+				Kind: pvcDataSource,
+				Name: pvc.Name,
+			},
+		},
+	}
+
+	err = controllerutil.SetControllerReference(pvc, vr, r.scheme)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("creating volumeReplication for claim", "VRCName", className, "ReplicationState", state)
+
+	err = r.Create(ctx, vr)
+	if err != nil {
+		logger.Error(err, "failed to create volumeReplication")
+
+		return err
+	}
+
+	r.recordEvent(pvc, corev1.EventTypeNormal, "VolumeReplicationCreated",
+		fmt.Sprintf("created VolumeReplication with class %q as %s", className, state))
+
+	return nil
+}
+
+// deleteVolumeReplication deletes the VolumeReplication of a claim that is no
+// longer annotated or is being deleted.
+func (r *autoCreateReconciler) deleteVolumeReplication(
+	ctx context.Context,
+	logger logr.Logger,
+	pvc *corev1.PersistentVolumeClaim,
+	vr *replicationv1alpha1.VolumeReplication,
+	message string,
+) error {
+	if !vr.GetDeletionTimestamp().IsZero() {
+		return nil
+	}
+
+	logger.Info("deleting volumeReplication of claim", "VRName", vr.Name)
+
+	err := r.Delete(ctx, vr)
+	if err != nil {
+		logger.Error(err, "failed to delete volumeReplication", "VRName", vr.Name)
+
+		return client.IgnoreNotFound(err)
+	}
+
+	r.recordEvent(pvc, corev1.EventTypeNormal, "VolumeReplicationDeleted", message)
+
+	return nil
+}
+
+func (r *autoCreateReconciler) recordEvent(obj client.Object, eventType, reason, message string) {
+	if r.recorder == nil {
+		return
+	}
+
+	r.recorder.Event(obj, eventType, reason, message)
+}
+
+// pvcStorageClassIndexFunc returns the name of the StorageClass of the claim.
+func pvcStorageClassIndexFunc(obj client.Object) []string {
+	pvc, ok := obj.(*corev1.PersistentVolumeClaim)
+	if !ok || pvc.Spec.StorageClassName == nil {
+		return nil
+	}
+
+	return []string{*pvc.Spec.StorageClassName}
+}
+
+// deletionPredicate passes the updates setting the deletion timestamp of an
+// object, which the predicates on its content drop.
+func deletionPredicate() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc: func(event.CreateEvent) bool {
+			return false
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return e.ObjectOld.GetDeletionTimestamp().IsZero() != e.ObjectNew.GetDeletionTimestamp().IsZero()
+		},
+		DeleteFunc: func(event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// claimsForStorageClass returns the claims of the StorageClass.
+func (r *autoCreateReconciler) claimsForStorageClass(ctx context.Context, obj client.Object) []reconcile.Request {
+	pvcList := &corev1.PersistentVolumeClaimList{}
+
+	err := r.List(ctx, pvcList, client.MatchingFields{pvcStorageClassIndex: obj.GetName()})
+	if err != nil {
+		r.log.Error(err, "failed to list claims", "StorageClass", obj.GetName())
+
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(pvcList.Items))
+	for i := range pvcList.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: pvcList.Items[i].Name, Namespace: pvcList.Items[i].Namespace},
+		})
+	}
+
+	return requests
+}
+
+// setupWithManager builds the controller creating the VolumeReplications of
+// annotated claims.
+// The claims are indexed by StorageClass by the VolumeReplication controller.
+func (r *autoCreateReconciler) setupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("volumereplication-autocreate").
+		For(&corev1.PersistentVolumeClaim{}, builder.WithPredicates(
+			predicate.Or[client.Object](predicate.AnnotationChangedPredicate{}, deletionPredicate()))).
+		// a deleted VolumeReplication is created again and its class restored
+		Owns(&replicationv1alpha1.VolumeReplication{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&storagev1.StorageClass{},
+			handler.EnqueueRequestsFromMapFunc(r.claimsForStorageClass),
+			builder.WithPredicates(predicate.AnnotationChangedPredicate{})).
+		Complete(r)
+}
@@ -0,0 +1,276 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func createFakeAutoCreateReconciler(t *testing.T, obj ...runtime.Object) *autoCreateReconciler {
+	t.Helper()
+
+	reconciler := createFakeVolumeReplicationReconciler(t, obj...)
+
+	return &autoCreateReconciler{
+		Client:       reconciler.Client,
+		scheme:       reconciler.Scheme,
+		log:          logf.Log.WithName("controller_autocreate_test"),
+		driverName:   reconciler.DriverConfig.DriverName,
+		defaultState: replicationv1alpha1.Primary,
+	}
+}
+
+func TestAutoCreateVolumeReplication(t *testing.T) {
+	t.Parallel()
+
+	otherClass := mockVolumeReplicationClassObj.DeepCopy()
+	otherClass.Name = "other-driver-class"
+	otherClass.Spec.Provisioner = "other-driver"
+
+	storageClass := &storagev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "replicated",
+			Annotations: map[string]string{volumeReplicationClassAnnotation: mockVolumeReplicationClassObj.Name},
+		},
+	}
+
+	namespace := func(annotations map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: mockNamespace, Annotations: annotations}}
+	}
+
+	claim := func(annotations map[string]string, storageClassName *string) *corev1.PersistentVolumeClaim {
+		pvc := mockPersistentVolumeClaim.DeepCopy()
+		pvc.UID = "pvc-uid"
+		pvc.Annotations = annotations
+		pvc.Spec.StorageClassName = storageClassName
+
+		return pvc
+	}
+
+	tests := []struct {
+		name      string
+		pvc       *corev1.PersistentVolumeClaim
+		namespace *corev1.Namespace
+		wantClass string
+		wantState replicationv1alpha1.ReplicationState
+	}{
+		{
+			name:      "claim annotation",
+			pvc:       claim(map[string]string{volumeReplicationClassAnnotation: mockVolumeReplicationClassObj.Name}, nil),
+			namespace: namespace(nil),
+			wantClass: mockVolumeReplicationClassObj.Name,
+			wantState: replicationv1alpha1.Primary,
+		},
+		{
+			name:      "storage class annotation with namespace state",
+			pvc:       claim(nil, ptr.To(storageClass.Name)),
+			namespace: namespace(map[string]string{replicationStateAnnotation: "secondary"}),
+			wantClass: mockVolumeReplicationClassObj.Name,
+			wantState: replicationv1alpha1.Secondary,
+		},
+		{
+			name:      "invalid namespace state",
+			pvc:       claim(nil, ptr.To(storageClass.Name)),
+			namespace: namespace(map[string]string{replicationStateAnnotation: "resync"}),
+			wantClass: mockVolumeReplicationClassObj.Name,
+			wantState: replicationv1alpha1.Primary,
+		},
+		{
+			name:      "claim annotation opts out of storage class",
+			pvc:       claim(map[string]string{volumeReplicationClassAnnotation: ""}, ptr.To(storageClass.Name)),
+			namespace: namespace(nil),
+		},
+		{
+			name:      "class of another driver",
+			pvc:       claim(map[string]string{volumeReplicationClassAnnotation: otherClass.Name}, nil),
+			namespace: namespace(nil),
+		},
+		{
+			name:      "missing class",
+			pvc:       claim(map[string]string{volumeReplicationClassAnnotation: "missing"}, nil),
+			namespace: namespace(nil),
+		},
+		{
+			name:      "no annotation",
+			pvc:       claim(nil, nil),
+			namespace: namespace(nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			reconciler := createFakeAutoCreateReconciler(t,
+				tt.pvc, tt.namespace, storageClass, mockVolumeReplicationClassObj.DeepCopy(), otherClass)
+			key := types.NamespacedName{Name: tt.pvc.Name, Namespace: tt.pvc.Namespace}
+
+			_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+			require.NoError(t, err)
+
+			vr := &replicationv1alpha1.VolumeReplication{}
+
+			err = reconciler.Get(context.TODO(), key, vr)
+			if tt.wantClass == "" {
+				require.True(t, apierrors.IsNotFound(err))
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.wantClass, vr.Spec.VolumeReplicationClass)
+			require.Equal(t, tt.wantState, vr.Spec.ReplicationState)
+			require.Equal(t, pvcDataSource, vr.Spec.DataSource.Kind)
+			require.Equal(t, tt.pvc.Name, vr.Spec.DataSource.Name)
+			require.True(t, metav1.IsControlledBy(vr, tt.pvc))
+		})
+	}
+}
+
+func TestAutoCreateVolumeReplicationLifecycle(t *testing.T) {
+	t.Parallel()
+
+	pvc := mockPersistentVolumeClaim.DeepCopy()
+	pvc.UID = "pvc-uid"
+	pvc.Spec.StorageClassName = ptr.To("replicated")
+	storageClass := &storagev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "replicated",
+			Annotations: map[string]string{volumeReplicationClassAnnotation: mockVolumeReplicationClassObj.Name},
+		},
+	}
+
+	reconciler := createFakeAutoCreateReconciler(t,
+		pvc,
+		storageClass,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: mockNamespace}},
+		mockVolumeReplicationClassObj.DeepCopy(),
+	)
+
+	ctx := context.TODO()
+	key := types.NamespacedName{Name: pvc.Name, Namespace: pvc.Namespace}
+
+	require.Equal(t, []reconcile.Request{{NamespacedName: key}}, reconciler.claimsForStorageClass(ctx, storageClass))
+
+	_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	vr := &replicationv1alpha1.VolumeReplication{}
+	require.NoError(t, reconciler.Get(ctx, key, vr))
+
+	// the state of the VolumeReplication is left to the user
+	vr.Spec.ReplicationState = replicationv1alpha1.Secondary
+	require.NoError(t, reconciler.Update(ctx, vr))
+
+	_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	require.NoError(t, reconciler.Get(ctx, key, vr))
+	require.Equal(t, replicationv1alpha1.Secondary, vr.Spec.ReplicationState)
+
+	// the VolumeReplication is deleted once the annotation is removed
+	storageClass.Annotations = nil
+	require.NoError(t, reconciler.Update(ctx, storageClass))
+
+	_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	require.True(t, apierrors.IsNotFound(reconciler.Get(ctx, key, vr)))
+}
+
+func TestAutoCreateSkipsUnownedVolumeReplication(t *testing.T) {
+	t.Parallel()
+
+	pvc := mockPersistentVolumeClaim.DeepCopy()
+	pvc.UID = "pvc-uid"
+	pvc.Annotations = map[string]string{volumeReplicationClassAnnotation: "other-class"}
+
+	existing := mockVolumeReplicationObj.DeepCopy()
+	existing.Name = pvc.Name
+
+	reconciler := createFakeAutoCreateReconciler(t,
+		pvc,
+		existing,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: mockNamespace}},
+		mockVolumeReplicationClassObj.DeepCopy(),
+	)
+
+	key := types.NamespacedName{Name: pvc.Name, Namespace: pvc.Namespace}
+
+	_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	vr := &replicationv1alpha1.VolumeReplication{}
+	require.NoError(t, reconciler.Get(context.TODO(), key, vr))
+	require.Equal(t, mockVolumeReplicationClassObj.Name, vr.Spec.VolumeReplicationClass)
+	require.Empty(t, vr.OwnerReferences)
+}
+
+func TestAutoCreateDeletesVolumeReplicationOfDeletedClaim(t *testing.T) {
+	t.Parallel()
+
+	pvc := mockPersistentVolumeClaim.DeepCopy()
+	pvc.UID = "pvc-uid"
+	pvc.Annotations = map[string]string{volumeReplicationClassAnnotation: mockVolumeReplicationClassObj.Name}
+
+	reconciler := createFakeAutoCreateReconciler(t,
+		pvc,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: mockNamespace}},
+		mockVolumeReplicationClassObj.DeepCopy(),
+	)
+
+	ctx := context.TODO()
+	key := types.NamespacedName{Name: pvc.Name, Namespace: pvc.Namespace}
+
+	_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	// the VolumeReplication protects the claim until it is deleted
+	vr := &replicationv1alpha1.VolumeReplication{}
+	require.NoError(t, reconciler.Get(ctx, key, vr))
+	vr.Finalizers = []string{volumeReplicationFinalizer}
+	require.NoError(t, reconciler.Update(ctx, vr))
+
+	require.NoError(t, reconciler.Get(ctx, key, pvc))
+	oldPVC := pvc.DeepCopy()
+	pvc.Finalizers = []string{pvcReplicationFinalizer}
+	require.NoError(t, reconciler.Update(ctx, pvc))
+	require.NoError(t, reconciler.Delete(ctx, pvc))
+	require.NoError(t, reconciler.Get(ctx, key, pvc))
+
+	// the deletion of the claim is not dropped by the annotation predicate
+	require.True(t, deletionPredicate().Update(event.UpdateEvent{ObjectOld: oldPVC, ObjectNew: pvc}))
+
+	_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	require.NoError(t, reconciler.Get(ctx, key, vr))
+	require.False(t, vr.GetDeletionTimestamp().IsZero())
+}
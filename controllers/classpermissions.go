@@ -0,0 +1,233 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// getClassDenial returns the condition reason and message if the
+// VolumeReplication may not use the class, either because its namespace is
+// not allowed or because the quota of the namespace is used by admitted or
+// older VolumeReplications.
+func getClassDenial(
+	ctx context.Context,
+	c client.Reader,
+	instance *replicationv1alpha1.VolumeReplication,
+	vrc *replicationv1alpha1.VolumeReplicationClass,
+) (string, string, bool, error) {
+	if vrc.Spec.AllowedNamespaces != nil {
+		allowed, err := isNamespaceAllowed(ctx, c, vrc.Spec.AllowedNamespaces, instance.Namespace)
+		if err != nil {
+			return "", "", false, err
+		}
+
+		if !allowed {
+			return NamespaceNotAllowed,
+				fmt.Sprintf("namespace %q is not allowed to use the VolumeReplicationClass %q", instance.Namespace, vrc.Name), true, nil
+		}
+	}
+
+	if vrc.Spec.MaxVolumeReplicationsPerNamespace == nil {
+		return "", "", false, nil
+	}
+
+	used, err := countPrecedingClassUsers(ctx, c, instance, vrc.Name)
+	if err != nil {
+		return "", "", false, err
+	}
+
+	maxVRs := int(*vrc.Spec.MaxVolumeReplicationsPerNamespace)
+	if used >= maxVRs {
+		return QuotaExceeded,
+			fmt.Sprintf("namespace %q already has %d VolumeReplications using the VolumeReplicationClass %q, the maximum is %d",
+				instance.Namespace, used, vrc.Name, maxVRs), true, nil
+	}
+
+	return "", "", false, nil
+}
+
+// isNamespaceAllowed returns true if the namespace is listed by name or
+// matches the selector.
+func isNamespaceAllowed(ctx context.Context, c client.Reader, allowed *replicationv1alpha1.AllowedNamespaces, namespace string) (bool, error) {
+	if slices.Contains(allowed.Names, namespace) {
+		return true, nil
+	}
+
+	if allowed.Selector == nil {
+		return false, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(allowed.Selector)
+	if err != nil {
+		return false, err
+	}
+
+	ns := &corev1.Namespace{}
+
+	err = c.Get(ctx, types.NamespacedName{Name: namespace}, ns)
+	if err != nil {
+		return false, err
+	}
+
+	return selector.Matches(labels.Set(ns.Labels)), nil
+}
+
+// countPrecedingClassUsers returns the number of VolumeReplications of the
+// namespace using the class that precede the instance in the quota: the ones
+// admitted with the class whatever their age, so that an admission is never
+// undone, and the ones created before the instance. An instance that is not
+// created yet is newer than all of them.
+func countPrecedingClassUsers(
+	ctx context.Context,
+	c client.Reader,
+	instance *replicationv1alpha1.VolumeReplication,
+	vrcName string,
+) (int, error) {
+	vrList := &replicationv1alpha1.VolumeReplicationList{}
+
+	err := c.List(ctx, vrList, client.InNamespace(instance.Namespace))
+	if err != nil {
+		return 0, err
+	}
+
+	used := 0
+
+	for i := range vrList.Items {
+		vr := &vrList.Items[i]
+		if vr.Name == instance.Name || !vr.GetDeletionTimestamp().IsZero() || getRecordedClassName(vr) != vrcName {
+			continue
+		}
+
+		admitted := vr.Status.Enabled != nil && vr.Status.VolumeReplicationClass == vrcName
+		if admitted || isCreatedBefore(vr, instance) {
+			used++
+		}
+	}
+
+	return used, nil
+}
+
+// isCreatedBefore orders the VolumeReplications by creation time, then by
+// name.
+func isCreatedBefore(a, b *replicationv1alpha1.VolumeReplication) bool {
+	if b.CreationTimestamp.IsZero() {
+		return true
+	}
+
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+
+	return a.Name < b.Name
+}
+
+// classQuotaPredicate passes the events that might change the places used in
+// the quota of a class: the creation or deletion of a VolumeReplication, or a
+// change of its class.
+func classQuotaPredicate() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc: func(event.CreateEvent) bool {
+			return true
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldVR, ok := e.ObjectOld.(*replicationv1alpha1.VolumeReplication)
+			if !ok {
+				return false
+			}
+
+			newVR, ok := e.ObjectNew.(*replicationv1alpha1.VolumeReplication)
+			if !ok {
+				return false
+			}
+
+			return getRecordedClassName(oldVR) != getRecordedClassName(newVR) ||
+				oldVR.GetDeletionTimestamp().IsZero() != newVR.GetDeletionTimestamp().IsZero()
+		},
+		DeleteFunc: func(event.DeleteEvent) bool {
+			return true
+		},
+		GenericFunc: func(event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// volumeReplicationsForQuota returns the VolumeReplications of the namespace
+// that are not permitted to use their class.
+func (r *VolumeReplicationReconciler) volumeReplicationsForQuota(ctx context.Context, obj client.Object) []reconcile.Request {
+	vrList := &replicationv1alpha1.VolumeReplicationList{}
+
+	err := r.List(ctx, vrList, client.InNamespace(obj.GetNamespace()))
+	if err != nil {
+		r.Log.Error(err, "failed to list volumeReplications", "Namespace", obj.GetNamespace())
+
+		return nil
+	}
+
+	var requests []reconcile.Request
+
+	for i := range vrList.Items {
+		vr := &vrList.Items[i]
+
+		denied := findCondition(vr.Status.Conditions, ConditionClassNotPermitted)
+		if vr.Name == obj.GetName() || denied == nil || denied.Status != metav1.ConditionTrue {
+			continue
+		}
+
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: vr.Name, Namespace: vr.Namespace},
+		})
+	}
+
+	return requests
+}
+
+// volumeReplicationsForNamespace returns the VolumeReplications of the
+// namespace, as its labels decide which classes they may use.
+func (r *VolumeReplicationReconciler) volumeReplicationsForNamespace(ctx context.Context, obj client.Object) []reconcile.Request {
+	vrList := &replicationv1alpha1.VolumeReplicationList{}
+
+	err := r.List(ctx, vrList, client.InNamespace(obj.GetName()))
+	if err != nil {
+		r.Log.Error(err, "failed to list volumeReplications", "Namespace", obj.GetName())
+
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(vrList.Items))
+	for i := range vrList.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: vrList.Items[i].Name, Namespace: vrList.Items[i].Namespace},
+		})
+	}
+
+	return requests
+}
@@ -0,0 +1,268 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+	"github.com/csi-addons/volume-replication-operator/pkg/client/fake"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestGetClassDenial(t *testing.T) {
+	t.Parallel()
+
+	created := metav1.NewTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	vr := func(name, class string, age time.Duration) *replicationv1alpha1.VolumeReplication {
+		obj := mockVolumeReplicationObj.DeepCopy()
+		obj.Name = name
+		obj.Spec.VolumeReplicationClass = class
+		obj.CreationTimestamp = metav1.NewTime(created.Add(-age))
+
+		return obj
+	}
+
+	admitted := func(obj *replicationv1alpha1.VolumeReplication) *replicationv1alpha1.VolumeReplication {
+		obj.Status.Enabled = &replicationv1alpha1.EnabledReplication{LastEnableTime: &created}
+		obj.Status.VolumeReplicationClass = obj.Spec.VolumeReplicationClass
+
+		return obj
+	}
+
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: mockNamespace, Labels: map[string]string{"tier": "production"}},
+	}
+
+	tests := []struct {
+		name       string
+		allowed    *replicationv1alpha1.AllowedNamespaces
+		maxVRs     *int32
+		others     []runtime.Object
+		wantReason string
+	}{
+		{
+			name: "unrestricted class",
+		},
+		{
+			name:    "namespace listed by name",
+			allowed: &replicationv1alpha1.AllowedNamespaces{Names: []string{mockNamespace}},
+		},
+		{
+			name: "namespace selected by label",
+			allowed: &replicationv1alpha1.AllowedNamespaces{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "production"}},
+			},
+		},
+		{
+			name: "namespace not allowed",
+			allowed: &replicationv1alpha1.AllowedNamespaces{
+				Names:    []string{"other"},
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "staging"}},
+			},
+			wantReason: NamespaceNotAllowed,
+		},
+		{
+			name:   "quota used by newer volumeReplications",
+			maxVRs: ptr.To[int32](1),
+			others: []runtime.Object{vr("newer", mockVolumeReplicationClassObj.Name, -time.Hour)},
+		},
+		{
+			name:   "quota used by volumeReplications of other classes",
+			maxVRs: ptr.To[int32](1),
+			others: []runtime.Object{vr("older", "other-class", time.Hour)},
+		},
+		{
+			name:       "quota used by admitted newer volumeReplications",
+			maxVRs:     ptr.To[int32](1),
+			others:     []runtime.Object{admitted(vr("newer", mockVolumeReplicationClassObj.Name, -time.Hour))},
+			wantReason: QuotaExceeded,
+		},
+		{
+			name:       "quota used by older volumeReplications",
+			maxVRs:     ptr.To[int32](1),
+			others:     []runtime.Object{vr("older", mockVolumeReplicationClassObj.Name, time.Hour)},
+			wantReason: QuotaExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			instance := vr("instance", mockVolumeReplicationClassObj.Name, 0)
+			vrc := mockVolumeReplicationClassObj.DeepCopy()
+			vrc.Spec.AllowedNamespaces = tt.allowed
+			vrc.Spec.MaxVolumeReplicationsPerNamespace = tt.maxVRs
+
+			objs := append([]runtime.Object{instance, vrc, namespace.DeepCopy()}, tt.others...)
+			reconciler := createFakeVolumeReplicationReconciler(t, objs...)
+
+			reason, _, denied, err := getClassDenial(context.TODO(), reconciler, instance, vrc)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantReason != "", denied)
+			require.Equal(t, tt.wantReason, reason)
+		})
+	}
+}
+
+func TestReconcileClassNotPermitted(t *testing.T) {
+	t.Parallel()
+
+	volumeReplication := mockVolumeReplicationObj.DeepCopy()
+	volumeReplication.Spec.DataSource.Kind = pvcDataSource
+	volumeReplication.Spec.ReplicationState = replicationv1alpha1.Primary
+
+	vrc := mockVolumeReplicationClassObj.DeepCopy()
+	vrc.Spec.AllowedNamespaces = &replicationv1alpha1.AllowedNamespaces{Names: []string{"production"}}
+
+	reconciler := createFakeVolumeReplicationReconciler(t,
+		volumeReplication,
+		vrc,
+		mockPersistentVolumeClaim.DeepCopy(),
+		mockPersistentVolume.DeepCopy(),
+	)
+	driver := fake.NewStatefulReplicationClient(1)
+	reconciler.Replication = driver
+
+	ctx := context.TODO()
+	key := types.NamespacedName{Name: volumeReplication.Name, Namespace: volumeReplication.Namespace}
+
+	_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	latest := &replicationv1alpha1.VolumeReplication{}
+	require.NoError(t, reconciler.Get(ctx, key, latest))
+
+	denied := findCondition(latest.Status.Conditions, ConditionClassNotPermitted)
+	require.NotNil(t, denied)
+	require.Equal(t, NamespaceNotAllowed, denied.Reason)
+	require.Empty(t, driver.Calls())
+
+	// the deletion of a volumeReplication of the class requeues the denied
+	// ones
+	other := mockVolumeReplicationObj.DeepCopy()
+	other.Name = "other"
+	require.Equal(t, []reconcile.Request{{NamespacedName: key}}, reconciler.volumeReplicationsForQuota(ctx, other))
+
+	// allowing the namespace resumes the reconciliation
+	vrc.Spec.AllowedNamespaces.Names = append(vrc.Spec.AllowedNamespaces.Names, mockNamespace)
+	require.NoError(t, reconciler.Update(ctx, vrc))
+
+	_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	require.NoError(t, reconciler.Get(ctx, key, latest))
+	require.Nil(t, findCondition(latest.Status.Conditions, ConditionClassNotPermitted))
+	require.Equal(t, replicationv1alpha1.PrimaryState, latest.Status.State)
+	require.NotEmpty(t, driver.Calls())
+}
+
+func TestReconcileClassRestrictedAfterAdmission(t *testing.T) {
+	t.Parallel()
+
+	volumeReplication := mockVolumeReplicationObj.DeepCopy()
+	volumeReplication.Spec.DataSource.Kind = pvcDataSource
+	volumeReplication.Spec.ReplicationState = replicationv1alpha1.Primary
+
+	vrc := mockVolumeReplicationClassObj.DeepCopy()
+
+	reconciler := createFakeVolumeReplicationReconciler(t,
+		volumeReplication,
+		vrc,
+		mockPersistentVolumeClaim.DeepCopy(),
+		mockPersistentVolume.DeepCopy(),
+	)
+	driver := fake.NewStatefulReplicationClient(1)
+	reconciler.Replication = driver
+
+	ctx := context.TODO()
+	key := types.NamespacedName{Name: volumeReplication.Name, Namespace: volumeReplication.Namespace}
+
+	_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	latest := &replicationv1alpha1.VolumeReplication{}
+	require.NoError(t, reconciler.Get(ctx, key, latest))
+	require.NotNil(t, latest.Status.Enabled)
+
+	// restricting the class does not freeze the admitted volumeReplication
+	vrc.Spec.AllowedNamespaces = &replicationv1alpha1.AllowedNamespaces{Names: []string{"production"}}
+	require.NoError(t, reconciler.Update(ctx, vrc))
+
+	latest.Spec.ReplicationState = replicationv1alpha1.Secondary
+	require.NoError(t, reconciler.Update(ctx, latest))
+
+	_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	require.NoError(t, reconciler.Get(ctx, key, latest))
+	require.Nil(t, findCondition(latest.Status.Conditions, ConditionClassNotPermitted))
+	require.Contains(t, driver.Calls(), fake.DemoteVolume)
+
+	// but changing to another restricted class is denied
+	other := vrc.DeepCopy()
+	other.ResourceVersion = ""
+	other.Name = "other-class"
+	require.NoError(t, reconciler.Create(ctx, other))
+
+	latest.Spec.VolumeReplicationClass = other.Name
+	require.NoError(t, reconciler.Update(ctx, latest))
+
+	_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	require.NoError(t, reconciler.Get(ctx, key, latest))
+	denied := findCondition(latest.Status.Conditions, ConditionClassNotPermitted)
+	require.NotNil(t, denied)
+	require.Equal(t, NamespaceNotAllowed, denied.Reason)
+	require.Equal(t, vrc.Name, latest.Status.VolumeReplicationClass)
+
+	// and stays denied on the next reconcile
+	_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	require.NoError(t, reconciler.Get(ctx, key, latest))
+	require.NotNil(t, findCondition(latest.Status.Conditions, ConditionClassNotPermitted))
+}
+
+func TestClassQuotaPredicate(t *testing.T) {
+	t.Parallel()
+
+	vr := mockVolumeReplicationObj.DeepCopy()
+	pred := classQuotaPredicate()
+
+	require.True(t, pred.Create(event.CreateEvent{Object: vr}))
+	require.True(t, pred.Delete(event.DeleteEvent{Object: vr}))
+	require.False(t, pred.Update(event.UpdateEvent{ObjectOld: vr, ObjectNew: vr.DeepCopy()}))
+
+	moved := vr.DeepCopy()
+	moved.Spec.VolumeReplicationClass = "other-class"
+	require.True(t, pred.Update(event.UpdateEvent{ObjectOld: vr, ObjectNew: moved}))
+}
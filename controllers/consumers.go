@@ -0,0 +1,366 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"strconv"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+	"github.com/csi-addons/volume-replication-operator/controllers/statemachine"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// podPVCIndex indexes pods by the names of the claims they mount.
+	podPVCIndex = "spec.volumes.persistentVolumeClaim.claimName"
+	// volumeAttachmentPVIndex indexes VolumeAttachments by the name of the
+	// PersistentVolume they attach.
+	volumeAttachmentPVIndex = "spec.source.persistentVolumeName"
+
+	// forceDemotionAnnotation allows demoting a volume in use.
+	forceDemotionAnnotation = replicationParameterPrefix + "force-demotion"
+)
+
+// podPVCIndexFunc returns the names of the claims mounted by the pod.
+func podPVCIndexFunc(obj client.Object) []string {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return nil
+	}
+
+	var names []string
+
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil {
+			names = append(names, volume.PersistentVolumeClaim.ClaimName)
+		}
+	}
+
+	return names
+}
+
+// volumeAttachmentPVIndexFunc returns the name of the PersistentVolume
+// attached by the VolumeAttachment.
+func volumeAttachmentPVIndexFunc(obj client.Object) []string {
+	va, ok := obj.(*storagev1.VolumeAttachment)
+	if !ok || va.Spec.Source.PersistentVolumeName == nil {
+		return nil
+	}
+
+	return []string{*va.Spec.Source.PersistentVolumeName}
+}
+
+// isPodTerminated returns true if the pod no longer runs.
+func isPodTerminated(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
+
+// isClaimMountedReadOnly returns true if all the volumes of the pod using the
+// claim are read-only.
+func isClaimMountedReadOnly(pod *corev1.Pod, pvcName string) bool {
+	for _, volume := range pod.Spec.Volumes {
+		claim := volume.PersistentVolumeClaim
+		if claim != nil && claim.ClaimName == pvcName && !claim.ReadOnly {
+			return false
+		}
+	}
+
+	return true
+}
+
+// getSourcePVCNames returns the names of the claims replicated by the
+// VolumeReplication, the members for a VolumeGroup data source.
+func getSourcePVCNames(instance *replicationv1alpha1.VolumeReplication) []string {
+	if instance.Spec.DataSource.Kind == volumeGroupDataSource {
+		names := make([]string, 0, len(instance.Status.Members))
+		for _, member := range instance.Status.Members {
+			names = append(names, member.PVCName)
+		}
+
+		return names
+	}
+
+	return []string{instance.Spec.DataSource.Name}
+}
+
+// getVolumeConsumers returns the sorted consumers of the claims, as
+// "pod/<name>" for the pods that are not terminated and
+// "volumeattachment/<name>" for the VolumeAttachments of their volumes. With
+// skipReadOnly, the pods mounting the claims read-only are left out, as are
+// the VolumeAttachments to nodes only running such pods.
+func (r *VolumeReplicationReconciler) getVolumeConsumers(
+	ctx context.Context,
+	namespace string,
+	pvcNames []string,
+	skipReadOnly bool,
+) ([]string, error) {
+	consumers := map[string]bool{}
+
+	for _, pvcName := range pvcNames {
+		pods := &corev1.PodList{}
+
+		err := r.List(ctx, pods, client.InNamespace(namespace), client.MatchingFields{podPVCIndex: pvcName})
+		if err != nil {
+			return nil, err
+		}
+
+		// nodes running read-only pods, and whether they run read-write
+		// ones as well
+		readOnlyNodes := map[string]bool{}
+
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			if isPodTerminated(pod) {
+				continue
+			}
+
+			if skipReadOnly && isClaimMountedReadOnly(pod, pvcName) {
+				if _, ok := readOnlyNodes[pod.Spec.NodeName]; !ok {
+					readOnlyNodes[pod.Spec.NodeName] = true
+				}
+
+				continue
+			}
+
+			readOnlyNodes[pod.Spec.NodeName] = false
+			consumers["pod/"+pod.Name] = true
+		}
+
+		pvc := &corev1.PersistentVolumeClaim{}
+
+		err = r.Get(ctx, types.NamespacedName{Name: pvcName, Namespace: namespace}, pvc)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+
+		if err != nil || pvc.Spec.VolumeName == "" {
+			continue
+		}
+
+		vas := &storagev1.VolumeAttachmentList{}
+
+		err = r.List(ctx, vas, client.MatchingFields{volumeAttachmentPVIndex: pvc.Spec.VolumeName})
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range vas.Items {
+			if readOnlyNodes[vas.Items[i].Spec.NodeName] {
+				continue
+			}
+
+			consumers["volumeattachment/"+vas.Items[i].Name] = true
+		}
+	}
+
+	names := make([]string, 0, len(consumers))
+	for name := range consumers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// isDemotionForced returns true if the VolumeReplication allows demoting
+// the volume while it is in use.
+func isDemotionForced(instance *replicationv1alpha1.VolumeReplication) bool {
+	forced, err := strconv.ParseBool(instance.GetAnnotations()[forceDemotionAnnotation])
+
+	return err == nil && forced
+}
+
+// validateTransition rejects the transition if it is not allowed while the
+// volume is in use. It returns true if the transition was rejected, with the
+// reason recorded in the status.
+func (r *VolumeReplicationReconciler) validateTransition(
+	ctx context.Context,
+	logger logr.Logger,
+	instance *replicationv1alpha1.VolumeReplication,
+	transition statemachine.Transition,
+) (bool, error) {
+	if !transition.InUseChecked() {
+		removeDemotionBlockedCondition(&instance.Status.Conditions)
+
+		return false, nil
+	}
+
+	// a demotion only affects the pods writing to the volume, while the
+	// unsafe transitions discard the data read by all pods.
+	consumers, err := r.getVolumeConsumers(ctx, instance.Namespace, getSourcePVCNames(instance), !transition.UnsafeWhileInUse)
+	if err != nil {
+		logger.Error(err, "failed to get the consumers of the volume")
+
+		return false, err
+	}
+
+	vErr := transition.Validate(consumers)
+
+	switch {
+	case vErr == nil:
+		removeDemotionBlockedCondition(&instance.Status.Conditions)
+
+		return false, nil
+	case errors.Is(vErr, statemachine.ErrDemotionBlocked) && isDemotionForced(instance):
+		logger.Info("demoting volume in use", "Consumers", consumers)
+		r.recordEvent(instance, corev1.EventTypeWarning, "DemotionForced", vErr.Error())
+		removeDemotionBlockedCondition(&instance.Status.Conditions)
+
+		return false, nil
+	case errors.Is(vErr, statemachine.ErrDemotionBlocked):
+		logger.Info("blocking demotion of volume in use", "Consumers", consumers)
+		setDemotionBlockedCondition(&instance.Status.Conditions, instance.Generation, vErr.Error())
+	default:
+		logger.Info("rejecting unsafe transition", "Reason", vErr.Error())
+		setUnsafeTransitionCondition(&instance.Status.Conditions, instance.Generation, vErr.Error())
+	}
+
+	// the request is not requeued, the pod and VolumeAttachment watches
+	// trigger a new reconcile once the consumers are gone.
+	err = r.updateReplicationStatus(ctx, instance, logger, getCurrentReplicationState(instance), vErr.Error())
+	if err != nil {
+		return true, err
+	}
+
+	return true, nil
+}
+
+// podPredicate passes the pod events that can release a claim, so that
+// transitions rejected while the claim is in use are retried.
+func podPredicate() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc: func(event.CreateEvent) bool {
+			return false
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldPod, ok := e.ObjectOld.(*corev1.Pod)
+			if !ok {
+				return false
+			}
+
+			newPod, ok := e.ObjectNew.(*corev1.Pod)
+			if !ok {
+				return false
+			}
+
+			return !isPodTerminated(oldPod) && isPodTerminated(newPod)
+		},
+		DeleteFunc: func(event.DeleteEvent) bool {
+			return true
+		},
+		GenericFunc: func(event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// volumeAttachmentPredicate passes the deletion of VolumeAttachments, so
+// that demotions blocked while the volume is attached are retried.
+func volumeAttachmentPredicate() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc: func(event.CreateEvent) bool {
+			return false
+		},
+		UpdateFunc: func(event.UpdateEvent) bool {
+			return false
+		},
+		DeleteFunc: func(event.DeleteEvent) bool {
+			return true
+		},
+		GenericFunc: func(event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// volumeReplicationsForPod maps a pod to the VolumeReplications replicating
+// the claims it mounts.
+func (r *VolumeReplicationReconciler) volumeReplicationsForPod(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.volumeReplicationsForClaims(ctx, obj.GetNamespace(), podPVCIndexFunc(obj))
+}
+
+// volumeReplicationsForVolumeAttachment maps a VolumeAttachment to the
+// VolumeReplications replicating the claim bound to its PersistentVolume.
+func (r *VolumeReplicationReconciler) volumeReplicationsForVolumeAttachment(ctx context.Context, obj client.Object) []reconcile.Request {
+	pvNames := volumeAttachmentPVIndexFunc(obj)
+	if len(pvNames) == 0 {
+		return nil
+	}
+
+	pv := &corev1.PersistentVolume{}
+
+	err := r.Get(ctx, types.NamespacedName{Name: pvNames[0]}, pv)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			r.Log.Error(err, "failed to get persistentVolume", "PVName", pvNames[0])
+		}
+
+		return nil
+	}
+
+	if pv.Spec.ClaimRef == nil {
+		return nil
+	}
+
+	return r.volumeReplicationsForClaims(ctx, pv.Spec.ClaimRef.Namespace, []string{pv.Spec.ClaimRef.Name})
+}
+
+// volumeReplicationsForClaims returns the VolumeReplications replicating any
+// of the claims.
+func (r *VolumeReplicationReconciler) volumeReplicationsForClaims(ctx context.Context, namespace string, pvcNames []string) []reconcile.Request {
+	if len(pvcNames) == 0 {
+		return nil
+	}
+
+	var requests []reconcile.Request
+
+	for _, pvcName := range pvcNames {
+		vrList := &replicationv1alpha1.VolumeReplicationList{}
+
+		err := r.List(ctx, vrList, client.InNamespace(namespace), client.MatchingFields{volumeReplicationPVCIndex: pvcName})
+		if err != nil {
+			r.Log.Error(err, "failed to list volumeReplications", "PVCName", pvcName, "Namespace", namespace)
+
+			continue
+		}
+
+		for i := range vrList.Items {
+			request := reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: vrList.Items[i].Name, Namespace: vrList.Items[i].Namespace},
+			}
+			if !slices.Contains(requests, request) {
+				requests = append(requests, request)
+			}
+		}
+	}
+
+	return requests
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestGetVolumeConsumers(t *testing.T) {
+	t.Parallel()
+
+	pod := func(name, node string, readOnly bool) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: mockNamespace},
+			Spec: corev1.PodSpec{
+				NodeName: node,
+				Volumes: []corev1.Volume{{
+					Name: "data",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+							ClaimName: mockPVCName,
+							ReadOnly:  readOnly,
+						},
+					},
+				}},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+
+	pvName := mockPVName
+	attachment := func(name, node string) *storagev1.VolumeAttachment {
+		return &storagev1.VolumeAttachment{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: storagev1.VolumeAttachmentSpec{
+				Attacher: "test-driver",
+				NodeName: node,
+				Source:   storagev1.VolumeAttachmentSource{PersistentVolumeName: &pvName},
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		objs         []runtime.Object
+		skipReadOnly bool
+		want         []string
+	}{
+		{
+			name: "read-write pod and its attachment",
+			objs: []runtime.Object{pod("writer", "node-0", false), attachment("va-0", "node-0")},
+			want: []string{"pod/writer", "volumeattachment/va-0"},
+		},
+		{
+			name:         "read-only pod and its attachment are skipped",
+			objs:         []runtime.Object{pod("reader", "node-0", true), attachment("va-0", "node-0")},
+			skipReadOnly: true,
+			want:         []string{},
+		},
+		{
+			name: "read-only pod blocks unsafe transitions",
+			objs: []runtime.Object{pod("reader", "node-0", true), attachment("va-0", "node-0")},
+			want: []string{"pod/reader", "volumeattachment/va-0"},
+		},
+		{
+			name: "attachment shared with a read-write pod",
+			objs: []runtime.Object{
+				pod("reader", "node-0", true), pod("writer", "node-0", false),
+				attachment("va-0", "node-0"), attachment("va-1", "node-1"),
+			},
+			skipReadOnly: true,
+			want:         []string{"pod/writer", "volumeattachment/va-0", "volumeattachment/va-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			objs := append([]runtime.Object{mockPersistentVolumeClaim.DeepCopy()}, tt.objs...)
+			reconciler := createFakeVolumeReplicationReconciler(t, objs...)
+
+			consumers, err := reconciler.getVolumeConsumers(context.TODO(), mockNamespace, []string{mockPVCName}, tt.skipReadOnly)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, consumers)
+		})
+	}
+}
+
+func TestGetVolumeConsumersFailsClosed(t *testing.T) {
+	t.Parallel()
+
+	reconciler := createFakeVolumeReplicationReconciler(t, mockPersistentVolumeClaim.DeepCopy())
+
+	fakeClient, ok := reconciler.Client.(client.WithWatch)
+	require.True(t, ok)
+
+	reconciler.Client = interceptor.NewClient(fakeClient, interceptor.Funcs{
+		Get: func(context.Context, client.WithWatch, client.ObjectKey, client.Object, ...client.GetOption) error {
+			return errors.New("connection refused")
+		},
+	})
+
+	_, err := reconciler.getVolumeConsumers(context.TODO(), mockNamespace, []string{mockPVCName}, true)
+	require.ErrorContains(t, err, "connection refused")
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery"
+)
+
+const (
+	// crdPollInterval is the interval at which the VolumeReplication CRDs
+	// are looked up until they are established.
+	crdPollInterval = 5 * time.Second
+
+	volumeReplicationResource          = "volumereplications"
+	volumeReplicationClassResource     = "volumereplicationclasses"
+	volumeReplicationOperationResource = "volumereplicationoperations"
+	volumeReplicationPolicyResource    = "volumereplicationpolicies"
+)
+
+// crdWaiter waits in the background until the VolumeReplication CRDs are
+// established and then starts the VolumeReplication controller. It does not
+// block the manager, so health probes are served while waiting.
+type crdWaiter struct {
+	discovery discovery.DiscoveryInterface
+	log       logr.Logger
+	// resources are the resources waited for.
+	resources []string
+	// onEstablished is called once all the CRDs are served.
+	onEstablished func() error
+
+	mu       sync.RWMutex
+	notReady string
+}
+
+func newCRDWaiter(dc discovery.DiscoveryInterface, logger logr.Logger, onEstablished func() error) *crdWaiter {
+	return &crdWaiter{
+		discovery:     dc,
+		log:           logger,
+		resources:     []string{volumeReplicationResource, volumeReplicationClassResource},
+		onEstablished: onEstablished,
+		notReady:      "waiting for VolumeReplication CRDs to be discovered",
+	}
+}
+
+// check returns true once the CRDs are served and the controller was started.
+func (w *crdWaiter) check() (bool, error) {
+	missing, err := missingResources(w.discovery, replicationv1alpha1.GroupVersion.String(), w.resources...)
+	if err != nil {
+		w.log.Error(err, "failed to discover VolumeReplication resources")
+		w.setNotReady(fmt.Sprintf("failed to discover VolumeReplication CRDs: %v", err))
+
+		return false, nil
+	}
+
+	if len(missing) != 0 {
+		w.log.Info("resources do not exist", "Resources", missing)
+		w.setNotReady("waiting for CRDs: " + strings.Join(missing, ", "))
+
+		return false, nil
+	}
+
+	err = w.onEstablished()
+	if err != nil {
+		w.setNotReady(fmt.Sprintf("failed to start VolumeReplication controller: %v", err))
+
+		return false, err
+	}
+
+	w.setNotReady("")
+
+	return true, nil
+}
+
+func (w *crdWaiter) setNotReady(reason string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.notReady = reason
+}
+
+// ReadyCheck is a readyz checker that fails with the reason the controller
+// has not been started yet.
+func (w *crdWaiter) ReadyCheck(_ *http.Request) error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.notReady != "" {
+		return fmt.Errorf("%s", w.notReady)
+	}
+
+	return nil
+}
+
+// Start polls for the CRDs until they are established or the context is done.
+func (w *crdWaiter) Start(ctx context.Context) error {
+	err := wait.PollUntilContextCancel(ctx, crdPollInterval, true, func(_ context.Context) (bool, error) {
+		return w.check()
+	})
+	if err != nil && ctx.Err() == nil {
+		return err
+	}
+
+	return nil
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface, the
+// controller started by the waiter takes care of leader election.
+func (w *crdWaiter) NeedLeaderElection() bool {
+	return false
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func TestCRDWaiter(t *testing.T) {
+	t.Parallel()
+
+	dc := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{}}
+	calls := 0
+
+	waiter := newCRDWaiter(dc, logf.Log.WithName("crd_waiter_test"), func() error {
+		calls++
+
+		return nil
+	})
+
+	require.Error(t, waiter.ReadyCheck(nil))
+
+	done, err := waiter.check()
+	require.NoError(t, err)
+	require.False(t, done)
+	require.ErrorContains(t, waiter.ReadyCheck(nil), volumeReplicationClassResource)
+
+	dc.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: replicationv1alpha1.GroupVersion.String(),
+			APIResources: []metav1.APIResource{{Name: volumeReplicationResource}},
+		},
+	}
+
+	done, err = waiter.check()
+	require.NoError(t, err)
+	require.False(t, done)
+	require.EqualError(t, waiter.ReadyCheck(nil), "waiting for CRDs: "+volumeReplicationClassResource)
+	require.Equal(t, 0, calls)
+
+	dc.Resources[0].APIResources = append(dc.Resources[0].APIResources, metav1.APIResource{Name: volumeReplicationClassResource})
+
+	done, err = waiter.check()
+	require.NoError(t, err)
+	require.True(t, done)
+	require.NoError(t, waiter.ReadyCheck(nil))
+	require.Equal(t, 1, calls)
+}
@@ -0,0 +1,291 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+	"github.com/csi-addons/volume-replication-operator/controllers/statemachine"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// volumeReplicationDependsOnIndex indexes VolumeReplications by the names of
+// the VolumeReplications they depend on.
+const volumeReplicationDependsOnIndex = "spec.dependsOn.name"
+
+// volumeReplicationDependsOnIndexFunc returns the names of the
+// VolumeReplications the VolumeReplication depends on.
+func volumeReplicationDependsOnIndexFunc(obj client.Object) []string {
+	vr, ok := obj.(*replicationv1alpha1.VolumeReplication)
+	if !ok {
+		return nil
+	}
+
+	names := make([]string, 0, len(vr.Spec.DependsOn))
+	for _, dependency := range vr.Spec.DependsOn {
+		names = append(names, dependency.Name)
+	}
+
+	return names
+}
+
+// isPromotedPrimary returns true if the VolumeReplication completed its
+// promotion and is not about to be demoted.
+func isPromotedPrimary(vr *replicationv1alpha1.VolumeReplication) bool {
+	if vr.Spec.ReplicationState != replicationv1alpha1.Primary ||
+		vr.Status.State != replicationv1alpha1.PrimaryState {
+		return false
+	}
+
+	completed := findCondition(vr.Status.Conditions, ConditionCompleted)
+
+	return completed != nil && completed.Status == metav1.ConditionTrue &&
+		completed.ObservedGeneration == vr.Generation
+}
+
+// findDependencyCycle returns the dependency path leading back to the
+// VolumeReplication, or nil if it is not part of a cycle.
+func (r *VolumeReplicationReconciler) findDependencyCycle(
+	ctx context.Context,
+	instance *replicationv1alpha1.VolumeReplication,
+) ([]string, error) {
+	visited := map[string]bool{}
+
+	var visit func(path []string, dependsOn []string) ([]string, error)
+
+	visit = func(path []string, dependsOn []string) ([]string, error) {
+		for _, name := range dependsOn {
+			if name == instance.Name {
+				return append(slices.Clone(path), name), nil
+			}
+
+			if visited[name] {
+				continue
+			}
+
+			visited[name] = true
+
+			dependency := &replicationv1alpha1.VolumeReplication{}
+
+			err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: instance.Namespace}, dependency)
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+
+			if err != nil {
+				return nil, err
+			}
+
+			cycle, err := visit(append(path, name), volumeReplicationDependsOnIndexFunc(dependency))
+			if cycle != nil || err != nil {
+				return cycle, err
+			}
+		}
+
+		return nil, nil
+	}
+
+	return visit([]string{instance.Name}, volumeReplicationDependsOnIndexFunc(instance))
+}
+
+// getDependencyWait returns the reason and message for which the transition
+// has to wait for other VolumeReplications, or an empty reason if it can run.
+// Dependencies are promoted before their dependents, and dependents are
+// demoted before their dependencies.
+func (r *VolumeReplicationReconciler) getDependencyWait(
+	ctx context.Context,
+	instance *replicationv1alpha1.VolumeReplication,
+	transition statemachine.Transition,
+) (string, string, error) {
+	promotion := transition.Target == replicationv1alpha1.PrimaryState &&
+		transition.From != replicationv1alpha1.PrimaryState
+	demotion := transition.Target == replicationv1alpha1.SecondaryState &&
+		transition.From != replicationv1alpha1.SecondaryState
+
+	if !promotion && !demotion {
+		return "", "", nil
+	}
+
+	cycle, err := r.findDependencyCycle(ctx, instance)
+	if err != nil {
+		return "", "", err
+	}
+
+	if cycle != nil {
+		return DependencyCycle, "dependency cycle: " + strings.Join(cycle, " -> "), nil
+	}
+
+	if promotion {
+		for _, name := range volumeReplicationDependsOnIndexFunc(instance) {
+			dependency := &replicationv1alpha1.VolumeReplication{}
+
+			err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: instance.Namespace}, dependency)
+			if apierrors.IsNotFound(err) {
+				return DependencyNotPrimary, fmt.Sprintf("waiting for dependency %q to be created", name), nil
+			}
+
+			if err != nil {
+				return "", "", err
+			}
+
+			if !isPromotedPrimary(dependency) {
+				return DependencyNotPrimary, fmt.Sprintf("waiting for dependency %q to be promoted", name), nil
+			}
+		}
+
+		return "", "", nil
+	}
+
+	dependents := &replicationv1alpha1.VolumeReplicationList{}
+
+	err = r.List(ctx, dependents, client.InNamespace(instance.Namespace),
+		client.MatchingFields{volumeReplicationDependsOnIndex: instance.Name})
+	if err != nil {
+		return "", "", err
+	}
+
+	for i := range dependents.Items {
+		dependent := &dependents.Items[i]
+		if !dependent.GetDeletionTimestamp().IsZero() ||
+			dependent.Status.State == replicationv1alpha1.SecondaryState {
+			continue
+		}
+
+		return DependentNotSecondary, fmt.Sprintf("waiting for dependent %q to be demoted", dependent.Name), nil
+	}
+
+	return "", "", nil
+}
+
+// waitForDependencies holds the transition until the VolumeReplications it
+// depends on, or that depend on it, are in the expected state. It returns
+// true if the transition has to wait.
+func (r *VolumeReplicationReconciler) waitForDependencies(
+	ctx context.Context,
+	logger logr.Logger,
+	instance *replicationv1alpha1.VolumeReplication,
+	transition statemachine.Transition,
+) (bool, error) {
+	reason, msg, err := r.getDependencyWait(ctx, instance, transition)
+	if err != nil {
+		logger.Error(err, "failed to check the dependencies of the volumeReplication")
+
+		return false, err
+	}
+
+	if reason == "" {
+		removeWaitingForDependencyCondition(&instance.Status.Conditions)
+
+		return false, nil
+	}
+
+	logger.Info("holding transition", "Reason", reason, "Message", msg)
+	setWaitingForDependencyCondition(&instance.Status.Conditions, instance.Generation, reason, msg)
+
+	// the request is not requeued, the VolumeReplication watch triggers a
+	// new reconcile once the dependencies change their state.
+	err = r.updateReplicationStatus(ctx, instance, logger, getCurrentReplicationState(instance), msg)
+	if err != nil {
+		return true, err
+	}
+
+	return true, nil
+}
+
+// dependencyPredicate passes the VolumeReplication changes the dependencies
+// and dependents of a VolumeReplication wait for.
+func dependencyPredicate() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc: func(event.CreateEvent) bool {
+			return true
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldVR, ok := e.ObjectOld.(*replicationv1alpha1.VolumeReplication)
+			if !ok {
+				return false
+			}
+
+			newVR, ok := e.ObjectNew.(*replicationv1alpha1.VolumeReplication)
+			if !ok {
+				return false
+			}
+
+			return oldVR.Status.State != newVR.Status.State ||
+				isPromotedPrimary(oldVR) != isPromotedPrimary(newVR) ||
+				!slices.Equal(oldVR.Spec.DependsOn, newVR.Spec.DependsOn)
+		},
+		DeleteFunc: func(event.DeleteEvent) bool {
+			return true
+		},
+		GenericFunc: func(event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// volumeReplicationsForDependency returns the dependencies and dependents of
+// the VolumeReplication.
+func (r *VolumeReplicationReconciler) volumeReplicationsForDependency(
+	ctx context.Context,
+	obj client.Object,
+) []reconcile.Request {
+	vr, ok := obj.(*replicationv1alpha1.VolumeReplication)
+	if !ok {
+		return nil
+	}
+
+	var requests []reconcile.Request
+
+	for _, name := range volumeReplicationDependsOnIndexFunc(vr) {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: name, Namespace: vr.Namespace},
+		})
+	}
+
+	dependents := &replicationv1alpha1.VolumeReplicationList{}
+
+	err := r.List(ctx, dependents, client.InNamespace(vr.Namespace),
+		client.MatchingFields{volumeReplicationDependsOnIndex: vr.Name})
+	if err != nil {
+		r.Log.Error(err, "failed to list dependent volumeReplications", "VRName", vr.Name, "Namespace", vr.Namespace)
+
+		return requests
+	}
+
+	for i := range dependents.Items {
+		request := reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: dependents.Items[i].Name, Namespace: dependents.Items[i].Namespace},
+		}
+		if !slices.Contains(requests, request) {
+			requests = append(requests, request)
+		}
+	}
+
+	return requests
+}
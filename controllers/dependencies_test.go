@@ -0,0 +1,237 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+	"github.com/csi-addons/volume-replication-operator/pkg/client/fake"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// mockDependency returns a VolumeReplication depending on the given ones.
+func mockDependency(name string, dependsOn ...string) *replicationv1alpha1.VolumeReplication {
+	vr := &replicationv1alpha1.VolumeReplication{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: mockNamespace},
+	}
+
+	for _, dependency := range dependsOn {
+		vr.Spec.DependsOn = append(vr.Spec.DependsOn, corev1.LocalObjectReference{Name: dependency})
+	}
+
+	return vr
+}
+
+func TestFindDependencyCycle(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		objects []runtime.Object
+		want    []string
+	}{
+		{
+			name:    "no dependencies",
+			objects: []runtime.Object{mockDependency("app")},
+		},
+		{
+			name:    "chain",
+			objects: []runtime.Object{mockDependency("app", "db"), mockDependency("db", "cache"), mockDependency("cache")},
+		},
+		{
+			name:    "missing dependency",
+			objects: []runtime.Object{mockDependency("app", "db")},
+		},
+		{
+			name:    "self dependency",
+			objects: []runtime.Object{mockDependency("app", "app")},
+			want:    []string{"app", "app"},
+		},
+		{
+			name:    "cycle",
+			objects: []runtime.Object{mockDependency("app", "db"), mockDependency("db", "cache"), mockDependency("cache", "app")},
+			want:    []string{"app", "db", "cache", "app"},
+		},
+		{
+			name: "unrelated cycle",
+			objects: []runtime.Object{
+				mockDependency("app", "db"), mockDependency("db", "cache"), mockDependency("cache", "db"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			reconciler := createFakeVolumeReplicationReconciler(t, tt.objects...)
+
+			cycle, err := reconciler.findDependencyCycle(context.TODO(), tt.objects[0].(*replicationv1alpha1.VolumeReplication))
+			require.NoError(t, err)
+			require.Equal(t, tt.want, cycle)
+		})
+	}
+}
+
+func TestReconcileWaitsForDependencyPromotion(t *testing.T) {
+	t.Parallel()
+
+	volumeReplication := mockVolumeReplicationObj.DeepCopy()
+	volumeReplication.Spec.DataSource.Kind = pvcDataSource
+	volumeReplication.Spec.ReplicationState = replicationv1alpha1.Primary
+	volumeReplication.Spec.DependsOn = []corev1.LocalObjectReference{{Name: "db"}}
+
+	db := mockDependency("db")
+	db.Spec.ReplicationState = replicationv1alpha1.Primary
+
+	reconciler := createFakeVolumeReplicationReconciler(t,
+		volumeReplication,
+		db,
+		mockVolumeReplicationClassObj.DeepCopy(),
+		mockPersistentVolumeClaim.DeepCopy(),
+		mockPersistentVolume.DeepCopy(),
+	)
+	driver := fake.NewStatefulReplicationClient(1)
+	reconciler.Replication = driver
+
+	key := types.NamespacedName{Name: volumeReplication.Name, Namespace: volumeReplication.Namespace}
+
+	result, err := reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	require.Equal(t, ctrl.Result{}, result)
+
+	latest := &replicationv1alpha1.VolumeReplication{}
+	require.NoError(t, reconciler.Get(context.TODO(), key, latest))
+
+	waiting := findCondition(latest.Status.Conditions, ConditionWaitingForDependency)
+	require.NotNil(t, waiting)
+	require.Equal(t, DependencyNotPrimary, waiting.Reason)
+	require.Contains(t, waiting.Message, `"db"`)
+	require.NotContains(t, driver.Calls(), fake.PromoteVolume)
+
+	// the promotion of the dependency triggers a new reconcile
+	require.NoError(t, reconciler.Get(context.TODO(), types.NamespacedName{Name: "db", Namespace: mockNamespace}, db))
+	db.Status.State = replicationv1alpha1.PrimaryState
+	setPromotedCondition(&db.Status.Conditions, db.Generation)
+	require.NoError(t, reconciler.Status().Update(context.TODO(), db))
+
+	requests := reconciler.volumeReplicationsForDependency(context.TODO(), db)
+	require.Equal(t, []reconcile.Request{{NamespacedName: key}}, requests)
+
+	_, err = reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	require.NoError(t, reconciler.Get(context.TODO(), key, latest))
+	require.Nil(t, findCondition(latest.Status.Conditions, ConditionWaitingForDependency))
+	require.Equal(t, replicationv1alpha1.PrimaryState, latest.Status.State)
+	require.Contains(t, driver.Calls(), fake.PromoteVolume)
+}
+
+func TestReconcileWaitsForDependentDemotion(t *testing.T) {
+	t.Parallel()
+
+	volumeReplication := mockVolumeReplicationObj.DeepCopy()
+	volumeReplication.Spec.DataSource.Kind = pvcDataSource
+	volumeReplication.Spec.ReplicationState = replicationv1alpha1.Secondary
+	volumeReplication.Status.State = replicationv1alpha1.PrimaryState
+
+	app := mockDependency("app", volumeReplication.Name)
+	app.Spec.ReplicationState = replicationv1alpha1.Secondary
+	app.Status.State = replicationv1alpha1.PrimaryState
+
+	reconciler := createFakeVolumeReplicationReconciler(t,
+		volumeReplication,
+		app,
+		mockVolumeReplicationClassObj.DeepCopy(),
+		mockPersistentVolumeClaim.DeepCopy(),
+		mockPersistentVolume.DeepCopy(),
+	)
+	driver := fake.NewStatefulReplicationClient(1)
+	reconciler.Replication = driver
+
+	key := types.NamespacedName{Name: volumeReplication.Name, Namespace: volumeReplication.Namespace}
+
+	_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	latest := &replicationv1alpha1.VolumeReplication{}
+	require.NoError(t, reconciler.Get(context.TODO(), key, latest))
+
+	waiting := findCondition(latest.Status.Conditions, ConditionWaitingForDependency)
+	require.NotNil(t, waiting)
+	require.Equal(t, DependentNotSecondary, waiting.Reason)
+	require.Equal(t, replicationv1alpha1.PrimaryState, latest.Status.State)
+	require.NotContains(t, driver.Calls(), fake.DemoteVolume)
+
+	// the demotion of the dependent releases the demotion
+	require.NoError(t, reconciler.Get(context.TODO(), types.NamespacedName{Name: "app", Namespace: mockNamespace}, app))
+	app.Status.State = replicationv1alpha1.SecondaryState
+	require.NoError(t, reconciler.Status().Update(context.TODO(), app))
+
+	requests := reconciler.volumeReplicationsForDependency(context.TODO(), app)
+	require.Equal(t, []reconcile.Request{{NamespacedName: key}}, requests)
+
+	_, err = reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	require.NoError(t, reconciler.Get(context.TODO(), key, latest))
+	require.Nil(t, findCondition(latest.Status.Conditions, ConditionWaitingForDependency))
+	require.Equal(t, replicationv1alpha1.SecondaryState, latest.Status.State)
+	require.Contains(t, driver.Calls(), fake.DemoteVolume)
+}
+
+func TestReconcileRejectsDependencyCycle(t *testing.T) {
+	t.Parallel()
+
+	volumeReplication := mockVolumeReplicationObj.DeepCopy()
+	volumeReplication.Spec.DataSource.Kind = pvcDataSource
+	volumeReplication.Spec.ReplicationState = replicationv1alpha1.Primary
+	volumeReplication.Spec.DependsOn = []corev1.LocalObjectReference{{Name: "db"}}
+
+	reconciler := createFakeVolumeReplicationReconciler(t,
+		volumeReplication,
+		mockDependency("db", volumeReplication.Name),
+		mockVolumeReplicationClassObj.DeepCopy(),
+		mockPersistentVolumeClaim.DeepCopy(),
+		mockPersistentVolume.DeepCopy(),
+	)
+	driver := fake.NewStatefulReplicationClient(1)
+	reconciler.Replication = driver
+
+	key := types.NamespacedName{Name: volumeReplication.Name, Namespace: volumeReplication.Namespace}
+
+	_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	latest := &replicationv1alpha1.VolumeReplication{}
+	require.NoError(t, reconciler.Get(context.TODO(), key, latest))
+
+	waiting := findCondition(latest.Status.Conditions, ConditionWaitingForDependency)
+	require.NotNil(t, waiting)
+	require.Equal(t, DependencyCycle, waiting.Reason)
+	require.Equal(t, "dependency cycle: volume-replication -> db -> volume-replication", waiting.Message)
+	require.NotContains(t, driver.Calls(), fake.PromoteVolume)
+}
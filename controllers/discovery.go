@@ -0,0 +1,61 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/discovery"
+)
+
+// resourcesAvailable checks through discovery whether all the given resources
+// are served for the group version.
+func resourcesAvailable(dc discovery.DiscoveryInterface, groupVersion string, resources ...string) (bool, error) {
+	missing, err := missingResources(dc, groupVersion, resources...)
+	if err != nil {
+		return false, err
+	}
+
+	return len(missing) == 0, nil
+}
+
+// missingResources returns the resources that are not served for the group
+// version.
+func missingResources(dc discovery.DiscoveryInterface, groupVersion string, resources ...string) ([]string, error) {
+	resourceList, err := dc.ServerResourcesForGroupVersion(groupVersion)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return resources, nil
+		}
+
+		return nil, err
+	}
+
+	served := make(map[string]bool, len(resourceList.APIResources))
+	for _, resource := range resourceList.APIResources {
+		served[resource.Name] = true
+	}
+
+	var missing []string
+
+	for _, resource := range resources {
+		if !served[resource] {
+			missing = append(missing, resource)
+		}
+	}
+
+	return missing, nil
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+	grpcClient "github.com/csi-addons/volume-replication-operator/pkg/client"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// dryRunAnnotation enables the dry-run mode for a single VolumeReplication.
+const dryRunAnnotation = replicationParameterPrefix + "dry-run"
+
+// isDryRun returns true if the VolumeReplication is reconciled in dry-run
+// mode, either for the whole operator or through its annotation.
+func (r *VolumeReplicationReconciler) isDryRun(instance *replicationv1alpha1.VolumeReplication) bool {
+	if r.DriverConfig != nil && r.DriverConfig.DryRun {
+		return true
+	}
+
+	value, ok := instance.GetAnnotations()[dryRunAnnotation]
+	if !ok {
+		return false
+	}
+
+	dryRun, err := strconv.ParseBool(value)
+
+	return err == nil && dryRun
+}
+
+// dryRunReconcile reconciles a copy of the VolumeReplication with a
+// reconciler that records the replication RPCs instead of issuing them, and
+// sends all its writes to the API server in dry-run mode. The recorded RPCs
+// are reported in the status and as an event, the rest of the status is left
+// untouched.
+func (r *VolumeReplicationReconciler) dryRunReconcile(
+	ctx context.Context,
+	logger logr.Logger,
+	req ctrl.Request,
+	instance *replicationv1alpha1.VolumeReplication,
+) (ctrl.Result, error) {
+	recorder := grpcClient.NewDryRunReplication()
+
+	dryRun := *r
+	dryRun.Client = client.NewDryRunClient(r.Client)
+	dryRun.Replication = recorder
+	dryRun.Recorder = nil
+	dryRun.rpcBudget = nil
+	// the requeues of the dry-run are not returned, so they must not move
+	// the backoff of the real reconciles
+	dryRun.requeueBackoff = newRequeueBackoff()
+	dryRun.podExec = dryRunPodExecutor{}
+	// the commands are not run, so they complete inline
+	dryRun.execHooks = nil
+
+	shadow := instance.DeepCopy()
+
+	_, err := dryRun.reconcile(ctx, logger.WithValues("DryRun", true), req, shadow)
+
+	message := shadow.Status.Message
+	if err != nil && message == "" {
+		message = err.Error()
+	}
+
+	report := &replicationv1alpha1.DryRunStatus{
+		Time:               getCurrentTime(),
+		ObservedGeneration: instance.Generation,
+		RPCs:               dryRunRPCs(recorder.Calls()),
+		Message:            message,
+	}
+
+	logger.Info("dry-run reconciliation", "RPCs", len(report.RPCs), "Message", message)
+	r.recordEvent(instance, corev1.EventTypeNormal, "DryRun", dryRunEventMessage(report.RPCs))
+
+	pErr := r.patchStatus(ctx, instance, func(status *replicationv1alpha1.VolumeReplicationStatus) {
+		// keep the previous report if only its time differs, to avoid a
+		// status write on every reconcile
+		if status.DryRun != nil {
+			report.Time = status.DryRun.Time
+			if !equality.Semantic.DeepEqual(status.DryRun, report) {
+				report.Time = getCurrentTime()
+			}
+		}
+
+		status.DryRun = report
+	})
+	if pErr != nil {
+		logger.Error(pErr, "failed to update volumeReplication status", "VRName", instance.Name)
+
+		return ctrl.Result{}, pErr
+	}
+
+	// the dry-run is not requeued, as the status it would have written is
+	// not persisted the requeue would only repeat the same operations.
+	return ctrl.Result{}, err
+}
+
+// dryRunPodExecutor succeeds without running the commands of exec hooks.
+type dryRunPodExecutor struct{}
+
+func (dryRunPodExecutor) Exec(context.Context, *corev1.Pod, string, []string) (string, string, error) {
+	return "", "", nil
+}
+
+// dryRunRPCs converts the recorded calls to their status representation.
+func dryRunRPCs(calls []grpcClient.RecordedCall) []replicationv1alpha1.DryRunRPC {
+	rpcs := make([]replicationv1alpha1.DryRunRPC, 0, len(calls))
+	for _, call := range calls {
+		rpcs = append(rpcs, replicationv1alpha1.DryRunRPC{
+			Operation:     call.Operation,
+			Source:        call.Source,
+			ReplicationID: call.ReplicationID,
+			Force:         call.Force,
+			Parameters:    call.Parameters,
+			SecretKeys:    call.SecretKeys,
+		})
+	}
+
+	return rpcs
+}
+
+// dryRunEventMessage returns the event message listing the RPCs that would
+// have been issued.
+func dryRunEventMessage(rpcs []replicationv1alpha1.DryRunRPC) string {
+	if len(rpcs) == 0 {
+		return "dry-run: no replication RPCs would be issued"
+	}
+
+	ops := make([]string, 0, len(rpcs))
+	for _, rpc := range rpcs {
+		op := fmt.Sprintf("%s(%s)", rpc.Operation, rpc.Source)
+		if rpc.Force {
+			op = fmt.Sprintf("%s(%s, force)", rpc.Operation, rpc.Source)
+		}
+
+		ops = append(ops, op)
+	}
+
+	return "dry-run: would issue " + strings.Join(ops, ", ")
+}
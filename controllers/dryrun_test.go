@@ -0,0 +1,96 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestIsDryRun(t *testing.T) {
+	t.Parallel()
+
+	reconciler := createFakeVolumeReplicationReconciler(t)
+
+	volumeReplication := mockVolumeReplicationObj.DeepCopy()
+	require.False(t, reconciler.isDryRun(volumeReplication))
+
+	volumeReplication.Annotations = map[string]string{dryRunAnnotation: "invalid"}
+	require.False(t, reconciler.isDryRun(volumeReplication))
+
+	volumeReplication.Annotations[dryRunAnnotation] = "true"
+	require.True(t, reconciler.isDryRun(volumeReplication))
+
+	reconciler.DriverConfig.DryRun = true
+	require.True(t, reconciler.isDryRun(mockVolumeReplicationObj.DeepCopy()))
+}
+
+func TestDryRunReconcile(t *testing.T) {
+	t.Parallel()
+
+	volumeReplication := mockVolumeReplicationObj.DeepCopy()
+	volumeReplication.Annotations = map[string]string{dryRunAnnotation: "true"}
+	volumeReplication.Spec.DataSource.Kind = pvcDataSource
+	volumeReplication.Spec.ReplicationState = replicationv1alpha1.Primary
+
+	// no driver connection is set up, any real RPC would panic
+	reconciler := createFakeVolumeReplicationReconciler(t,
+		volumeReplication,
+		mockVolumeReplicationClassObj.DeepCopy(),
+		mockPersistentVolumeClaim.DeepCopy(),
+		mockPersistentVolume.DeepCopy(),
+	)
+
+	key := types.NamespacedName{Name: volumeReplication.Name, Namespace: volumeReplication.Namespace}
+
+	reconciler.requeueBackoff = newRequeueBackoff()
+	reconciler.requeueBackoff.next(key.String(), time.Second, time.Minute, 0)
+
+	result, err := reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	require.Equal(t, ctrl.Result{}, result)
+
+	// the completed dry-run does not reset the backoff of the real reconciles
+	require.Equal(t, 1, reconciler.requeueBackoff.attempts[key.String()])
+
+	latest := &replicationv1alpha1.VolumeReplication{}
+	require.NoError(t, reconciler.Get(context.TODO(), key, latest))
+	require.Empty(t, latest.Finalizers)
+	require.Empty(t, latest.Status.State)
+	require.Nil(t, latest.Status.Enabled)
+	require.NotNil(t, latest.Status.DryRun)
+	require.Equal(t, "volume is marked primary", latest.Status.DryRun.Message)
+	require.Equal(t, []replicationv1alpha1.DryRunRPC{
+		{Operation: "EnableVolumeReplication", Source: "volume:" + mockVolumeHandle},
+		{Operation: "PromoteVolume", Source: "volume:" + mockVolumeHandle},
+	}, latest.Status.DryRun.RPCs)
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	require.NoError(t, reconciler.Get(context.TODO(), types.NamespacedName{Name: mockPVCName, Namespace: mockNamespace}, pvc))
+	require.Empty(t, pvc.Finalizers)
+
+	require.Equal(t, "dry-run: would issue EnableVolumeReplication(volume:test-volume-handle), "+
+		"PromoteVolume(volume:test-volume-handle)", dryRunEventMessage(latest.Status.DryRun.RPCs))
+}
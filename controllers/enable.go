@@ -0,0 +1,79 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"time"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+)
+
+// enableInputsHash returns a hash over the inputs EnableVolumeReplication is
+// called with: the parameters passed to the driver and the secret reference.
+// The secret contents are tracked by the resourceVersion of the secret, and
+// the reserved parameters only used by the operator are left out so that
+// tuning them does not enable replication again.
+func enableInputsHash(kind, volumeHandle, replicationHandle, secretName, secretNamespace string, parameters map[string]string) string {
+	keys := make([]string, 0, len(parameters))
+	for key := range parameters {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	h := sha256.New()
+
+	for _, s := range []string{kind, volumeHandle, replicationHandle, secretName, secretNamespace} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+
+	for _, key := range keys {
+		h.Write([]byte(key))
+		h.Write([]byte{0})
+		h.Write([]byte(parameters[key]))
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// needsEnable returns true if replication has to be enabled, because it was
+// never enabled, its inputs changed since, or the drift verification interval
+// has passed.
+func needsEnable(enabled *replicationv1alpha1.EnabledReplication, inputsHash, secretVersion string,
+	verifyInterval time.Duration, now time.Time,
+) bool {
+	if enabled == nil {
+		return true
+	}
+
+	if enabled.InputsHash != inputsHash || enabled.SecretResourceVersion != secretVersion {
+		return true
+	}
+
+	if verifyInterval > 0 {
+		if enabled.LastEnableTime == nil || now.Sub(enabled.LastEnableTime.Time) >= verifyInterval {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+	"github.com/csi-addons/volume-replication-operator/pkg/client/fake"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestEnableInputsHash(t *testing.T) {
+	t.Parallel()
+
+	params := map[string]string{"a": "1", "b": "2"}
+	hash := enableInputsHash(pvcDataSource, mockVolumeHandle, "", "secret", "storage", params)
+
+	require.Equal(t, hash, enableInputsHash(pvcDataSource, mockVolumeHandle, "", "secret", "storage", map[string]string{"b": "2", "a": "1"}))
+	require.NotEqual(t, hash, enableInputsHash(pvcDataSource, mockVolumeHandle, "", "secret", "storage", map[string]string{"a": "1", "b": "3"}))
+	require.NotEqual(t, hash, enableInputsHash(pvcDataSource, "other-handle", "", "secret", "storage", params))
+	require.NotEqual(t, hash, enableInputsHash(pvcDataSource, mockVolumeHandle, "handle", "secret", "storage", params))
+	require.NotEqual(t, hash, enableInputsHash(pvcDataSource, mockVolumeHandle, "", "other-secret", "storage", params))
+	require.NotEqual(t, hash, enableInputsHash(pvcDataSource, mockVolumeHandle, "", "secret", "other", params))
+}
+
+func TestNeedsEnable(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	enabledAt := metav1.NewTime(now.Add(-time.Hour))
+	enabled := &replicationv1alpha1.EnabledReplication{
+		InputsHash:            "hash",
+		SecretResourceVersion: "1",
+		LastEnableTime:        &enabledAt,
+	}
+
+	testcases := []struct {
+		name           string
+		enabled        *replicationv1alpha1.EnabledReplication
+		hash           string
+		secretVersion  string
+		verifyInterval time.Duration
+		expected       bool
+	}{
+		{name: "never enabled", enabled: nil, hash: "hash", secretVersion: "1", expected: true},
+		{name: "inputs unchanged", enabled: enabled, hash: "hash", secretVersion: "1", expected: false},
+		{name: "parameters changed", enabled: enabled, hash: "other", secretVersion: "1", expected: true},
+		{name: "secret changed", enabled: enabled, hash: "hash", secretVersion: "2", expected: true},
+		{name: "verify interval not passed", enabled: enabled, hash: "hash", secretVersion: "1", verifyInterval: 2 * time.Hour, expected: false},
+		{name: "verify interval passed", enabled: enabled, hash: "hash", secretVersion: "1", verifyInterval: time.Minute, expected: true},
+	}
+
+	for _, tc := range testcases {
+		require.Equal(t, tc.expected, needsEnable(tc.enabled, tc.hash, tc.secretVersion, tc.verifyInterval, now), tc.name)
+	}
+}
+
+func TestReconcileReservedParametersDoNotEnable(t *testing.T) {
+	t.Parallel()
+
+	volumeReplication := mockVolumeReplicationObj.DeepCopy()
+	volumeReplication.Spec.DataSource.Kind = pvcDataSource
+	volumeReplication.Spec.ReplicationState = replicationv1alpha1.Primary
+
+	vrc := mockVolumeReplicationClassObj.DeepCopy()
+	vrc.Spec.Parameters = map[string]string{"mirroringMode": "snapshot"}
+
+	reconciler := createFakeVolumeReplicationReconciler(t,
+		volumeReplication,
+		vrc,
+		mockPersistentVolumeClaim.DeepCopy(),
+		mockPersistentVolume.DeepCopy(),
+	)
+	driver := fake.NewStatefulReplicationClient(1)
+	reconciler.Replication = driver
+
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: volumeReplication.Name, Namespace: volumeReplication.Namespace}}
+
+	enables := func() int {
+		count := 0
+
+		for _, call := range driver.Calls() {
+			if call == fake.EnableVolumeReplication {
+				count++
+			}
+		}
+
+		return count
+	}
+
+	_, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, 1, enables())
+
+	// tuning the requeue of the class is not passed to the driver
+	vrc.Spec.Parameters[prefixedResyncRequeueIntervalKey] = "1m"
+	require.NoError(t, reconciler.Update(ctx, vrc))
+
+	_, err = reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, 1, enables())
+
+	// the parameters passed to the driver enable replication again
+	vrc.Spec.Parameters["mirroringMode"] = "journal"
+	require.NoError(t, reconciler.Update(ctx, vrc))
+
+	_, err = reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, 2, enables())
+}
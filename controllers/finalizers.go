@@ -26,6 +26,7 @@ import (
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
@@ -40,6 +41,7 @@ func (r *VolumeReplicationReconciler) addFinalizerToVR(ctx context.Context, logg
 	if !contains(vr.Finalizers, volumeReplicationFinalizer) {
 		logger.Info("adding finalizer to volumeReplication object", "Finalizer", volumeReplicationFinalizer)
 		vr.Finalizers = append(vr.Finalizers, volumeReplicationFinalizer)
+		status := vr.Status.DeepCopy()
 
 		err := r.Update(ctx, vr)
 		if err != nil {
@@ -47,6 +49,9 @@ func (r *VolumeReplicationReconciler) addFinalizerToVR(ctx context.Context, logg
 				" (%s/%s) %w",
 				volumeReplicationFinalizer, vr.Namespace, vr.Name, err)
 		}
+
+		// the update returns the stored status, keep the one being reconciled
+		vr.Status = *status
 	}
 
 	return nil
@@ -64,6 +69,9 @@ func (r *VolumeReplicationReconciler) removeFinalizerFromVR(ctx context.Context,
 				" (%s/%s), %w",
 				volumeReplicationFinalizer, vr.Namespace, vr.Name, err)
 		}
+
+		// the VolumeReplication is gone once its finalizer is removed
+		r.requeueBackoff.reset(client.ObjectKeyFromObject(vr).String())
 	}
 
 	return nil
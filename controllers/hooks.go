@@ -0,0 +1,529 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// defaultHookTimeout is the time a hook is allowed to run if it sets
+	// no timeout.
+	defaultHookTimeout = 5 * time.Minute
+	// hookPollInterval is the interval at which running hooks are checked,
+	// in addition to the Job watch for the Job hooks.
+	hookPollInterval = 10 * time.Second
+)
+
+// hooksOutcome is the outcome of running the hooks of a transition.
+type hooksOutcome int
+
+const (
+	// hooksCompleted is returned once all hooks succeeded or failed with
+	// the Ignore failure policy.
+	hooksCompleted hooksOutcome = iota
+	// hooksRunning is returned while a hook has not completed.
+	hooksRunning
+	// hooksFailed is returned once a hook failed with the Fail failure
+	// policy.
+	hooksFailed
+)
+
+// invalidJobNameChars matches the characters not allowed in Job names.
+var invalidJobNameChars = regexp.MustCompile(`[^a-z0-9-]`)
+
+// getTransitionHooks returns the hooks of the given type. The hooks are
+// defined by the VolumeReplicationClass, so that only the cluster admins
+// choose the commands and Jobs run by the operator.
+func getTransitionHooks(vrcObj *replicationv1alpha1.VolumeReplicationClass, hookType replicationv1alpha1.HookType) []replicationv1alpha1.TransitionHook {
+	if vrcObj.Spec.Hooks == nil {
+		return nil
+	}
+
+	if hookType == replicationv1alpha1.PreDemoteHook {
+		return vrcObj.Spec.Hooks.PreDemote
+	}
+
+	return vrcObj.Spec.Hooks.PostPromote
+}
+
+// isHookRunning returns true if a hook of the given type was started for the
+// current generation and has not completed.
+func isHookRunning(instance *replicationv1alpha1.VolumeReplication, hookType replicationv1alpha1.HookType) bool {
+	for _, status := range instance.Status.Hooks {
+		if status.Type == hookType && status.ObservedGeneration == instance.Generation &&
+			status.Phase == replicationv1alpha1.HookRunning {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getHookTimeout returns the time the hook is allowed to run.
+func getHookTimeout(hook *replicationv1alpha1.TransitionHook) time.Duration {
+	if hook.Timeout == nil || hook.Timeout.Duration <= 0 {
+		return defaultHookTimeout
+	}
+
+	return hook.Timeout.Duration
+}
+
+// getHookJobName returns the name of the Job of the hook, unique per
+// VolumeReplication, hook and generation.
+func getHookJobName(instance *replicationv1alpha1.VolumeReplication, hookType replicationv1alpha1.HookType, hookName string) string {
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%s/%s/%s/%d", instance.UID, hookType, hookName, instance.Generation)
+	suffix := fmt.Sprintf("%08x", h.Sum32())
+
+	base := invalidJobNameChars.ReplaceAllString(strings.ToLower(instance.Name+"-"+hookName), "-")
+	// Job names are used as a label value on their pods, which is limited
+	// to 63 characters.
+	if maxLen := 63 - len(suffix) - 1; len(base) > maxLen {
+		base = base[:maxLen]
+	}
+
+	return strings.Trim(base, "-") + "-" + suffix
+}
+
+// startHook returns the status of the hook for the current generation,
+// recording the hook as running if it was not started yet.
+func startHook(
+	instance *replicationv1alpha1.VolumeReplication,
+	hookType replicationv1alpha1.HookType,
+	hookName string,
+) *replicationv1alpha1.HookStatus {
+	started := replicationv1alpha1.HookStatus{
+		Name:               hookName,
+		Type:               hookType,
+		Phase:              replicationv1alpha1.HookRunning,
+		ObservedGeneration: instance.Generation,
+		StartTime:          getCurrentTime(),
+	}
+
+	for i := range instance.Status.Hooks {
+		status := &instance.Status.Hooks[i]
+		if status.Type == hookType && status.Name == hookName {
+			if status.ObservedGeneration != instance.Generation {
+				*status = started
+			}
+
+			return status
+		}
+	}
+
+	instance.Status.Hooks = append(instance.Status.Hooks, started)
+
+	return &instance.Status.Hooks[len(instance.Status.Hooks)-1]
+}
+
+// completeHook records the outcome of the hook.
+func completeHook(status *replicationv1alpha1.HookStatus, phase replicationv1alpha1.HookPhase, message string) {
+	status.Phase = phase
+	status.Message = message
+	status.CompletionTime = getCurrentTime()
+}
+
+// runTransitionHooks runs the hooks of the given type and reports their
+// outcome in the status. It returns true if the reconciliation must stop, as
+// a hook is still running or failed, with the status already updated with the
+// given state.
+func (r *VolumeReplicationReconciler) runTransitionHooks(
+	ctx context.Context,
+	logger logr.Logger,
+	instance *replicationv1alpha1.VolumeReplication,
+	vrcObj *replicationv1alpha1.VolumeReplicationClass,
+	hookType replicationv1alpha1.HookType,
+	state replicationv1alpha1.State,
+) (ctrl.Result, bool, error) {
+	hooks := getTransitionHooks(vrcObj, hookType)
+	if len(hooks) == 0 {
+		return ctrl.Result{}, false, nil
+	}
+
+	outcome, message := r.runHooks(ctx, logger, instance, hookType, hooks)
+	switch outcome {
+	case hooksCompleted:
+		removeHookFailedCondition(&instance.Status.Conditions)
+
+		return ctrl.Result{}, false, nil
+	case hooksRunning:
+		logger.Info("waiting for hook", "HookType", hookType, "Message", message)
+
+		err := r.updateReplicationStatus(ctx, instance, logger, state, message)
+		if err != nil {
+			return ctrl.Result{}, true, err
+		}
+
+		return ctrl.Result{RequeueAfter: hookPollInterval}, true, nil
+	default:
+		logger.Info("hook failed", "HookType", hookType, "Message", message)
+		setHookFailedCondition(&instance.Status.Conditions, instance.Generation, hookType, message)
+		r.recordEvent(instance, corev1.EventTypeWarning, "HookFailed", message)
+
+		// the hooks are only run again for a new generation, so the
+		// request is not requeued.
+		err := r.updateReplicationStatus(ctx, instance, logger, state, message)
+		if err != nil {
+			return ctrl.Result{}, true, err
+		}
+
+		return ctrl.Result{}, true, nil
+	}
+}
+
+// runHooks runs the hooks in order, each hook is only run once per
+// generation. It returns the outcome with a message describing it.
+func (r *VolumeReplicationReconciler) runHooks(
+	ctx context.Context,
+	logger logr.Logger,
+	instance *replicationv1alpha1.VolumeReplication,
+	hookType replicationv1alpha1.HookType,
+	hooks []replicationv1alpha1.TransitionHook,
+) (hooksOutcome, string) {
+	for i := range hooks {
+		hook := &hooks[i]
+
+		status := startHook(instance, hookType, hook.Name)
+		if status.Phase == replicationv1alpha1.HookRunning {
+			r.runHook(ctx, logger, instance, hookType, hook, status)
+		}
+
+		switch {
+		case status.Phase == replicationv1alpha1.HookRunning:
+			return hooksRunning, fmt.Sprintf("waiting for %s hook %q", hookType, hook.Name)
+		case status.Phase == replicationv1alpha1.HookFailed && hook.FailurePolicy == replicationv1alpha1.HookFailurePolicyIgnore:
+			logger.Info("ignoring failed hook", "HookType", hookType, "HookName", hook.Name, "Message", status.Message)
+		case status.Phase == replicationv1alpha1.HookFailed:
+			return hooksFailed, fmt.Sprintf("%s hook %q failed: %s", hookType, hook.Name, status.Message)
+		}
+	}
+
+	return hooksCompleted, ""
+}
+
+// runHook runs the hook, or checks on it if it was already started, and
+// records its outcome in the status once it completed.
+func (r *VolumeReplicationReconciler) runHook(
+	ctx context.Context,
+	logger logr.Logger,
+	instance *replicationv1alpha1.VolumeReplication,
+	hookType replicationv1alpha1.HookType,
+	hook *replicationv1alpha1.TransitionHook,
+	status *replicationv1alpha1.HookStatus,
+) {
+	switch {
+	case hook.Exec != nil && hook.Job == nil:
+		r.runExecHook(ctx, logger, instance, hookType, hook, status)
+	case hook.Job != nil && hook.Exec == nil:
+		r.runJobHook(ctx, logger, instance, hookType, hook, status)
+	default:
+		completeHook(status, replicationv1alpha1.HookFailed, "exactly one of exec and job must be set")
+	}
+}
+
+// execHookResult is the outcome of the command of an exec hook.
+type execHookResult struct {
+	phase   replicationv1alpha1.HookPhase
+	message string
+}
+
+// execHookRunner runs the commands of the exec hooks in the background, so
+// that they do not block the reconciles. The results are kept until they are
+// collected by a later reconcile, as the Jobs of the Job hooks are.
+type execHookRunner struct {
+	mu      sync.Mutex
+	results map[string]*execHookResult
+}
+
+func newExecHookRunner() *execHookRunner {
+	return &execHookRunner{results: map[string]*execHookResult{}}
+}
+
+// start runs the command in the background under the key.
+func (e *execHookRunner) start(key string, run func() execHookResult) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.results[key] = nil
+
+	go func() {
+		result := run()
+
+		e.mu.Lock()
+		defer e.mu.Unlock()
+
+		e.results[key] = &result
+	}()
+}
+
+// result returns the result of the command started under the key, nil while
+// it is running, and false if no command was started. A returned result is
+// forgotten.
+func (e *execHookRunner) result(key string) (*execHookResult, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	result, started := e.results[key]
+	if result != nil {
+		delete(e.results, key)
+	}
+
+	return result, started
+}
+
+// getExecHookKey returns the key of the command of the hook, unique per
+// VolumeReplication, hook and generation.
+func getExecHookKey(instance *replicationv1alpha1.VolumeReplication, hookType replicationv1alpha1.HookType, hookName string) string {
+	return fmt.Sprintf("%s/%s/%s/%d", instance.UID, hookType, hookName, instance.Generation)
+}
+
+// runExecHook runs the command of the hook in all running pods matching its
+// selector, or checks on it if it was already started. The hook fails if the
+// command fails in any pod and succeeds if no pod is running. The command is
+// run in the background, or inline without an execHookRunner, e.g. for the
+// dry run. A command lost to a restart of the operator is started again.
+func (r *VolumeReplicationReconciler) runExecHook(
+	ctx context.Context,
+	logger logr.Logger,
+	instance *replicationv1alpha1.VolumeReplication,
+	hookType replicationv1alpha1.HookType,
+	hook *replicationv1alpha1.TransitionHook,
+	status *replicationv1alpha1.HookStatus,
+) {
+	key := getExecHookKey(instance, hookType, hook.Name)
+
+	if r.execHooks != nil {
+		result, started := r.execHooks.result(key)
+		if result != nil {
+			completeHook(status, result.phase, result.message)
+
+			return
+		}
+
+		if started {
+			return
+		}
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&hook.Exec.Selector)
+	if err != nil {
+		completeHook(status, replicationv1alpha1.HookFailed, fmt.Sprintf("invalid selector: %v", err))
+
+		return
+	}
+
+	pods := &corev1.PodList{}
+
+	err = r.List(ctx, pods, client.InNamespace(instance.Namespace), client.MatchingLabelsSelector{Selector: selector})
+	if err != nil {
+		logger.Error(err, "failed to list pods of hook", "HookName", hook.Name)
+		status.Message = err.Error()
+
+		return
+	}
+
+	var running []*corev1.Pod
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp.IsZero() {
+			running = append(running, pod)
+		}
+	}
+
+	// there is nothing to quiesce or recover without running pods, e.g. for
+	// a volume created as secondary.
+	if len(running) == 0 {
+		completeHook(status, replicationv1alpha1.HookSucceeded, "no running pod matches the selector")
+
+		return
+	}
+
+	timeout := getHookTimeout(hook)
+	exec := hook.Exec.DeepCopy()
+	logger = logger.WithValues("HookName", hook.Name)
+
+	if r.execHooks == nil {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		result := r.execHookCommand(ctx, logger, exec, running)
+		completeHook(status, result.phase, result.message)
+
+		return
+	}
+
+	// the command outlives the reconcile, so it is not bound to its context.
+	r.execHooks.start(key, func() execHookResult {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		return r.execHookCommand(ctx, logger, exec, running)
+	})
+
+	status.Message = fmt.Sprintf("command running in %d pods", len(running))
+}
+
+// execHookCommand runs the command of the exec hook in the pods, stopping at
+// the first failure.
+func (r *VolumeReplicationReconciler) execHookCommand(
+	ctx context.Context,
+	logger logr.Logger,
+	exec *replicationv1alpha1.ExecHook,
+	pods []*corev1.Pod,
+) execHookResult {
+	for _, pod := range pods {
+		container := exec.Container
+		if container == "" {
+			container = pod.Spec.Containers[0].Name
+		}
+
+		logger.Info("running hook command", "Pod", pod.Name, "Container", container)
+
+		_, stderr, err := r.podExec.Exec(ctx, pod, container, exec.Command)
+		if err != nil {
+			message := fmt.Sprintf("pod/%s: %v", pod.Name, err)
+			if stderr = strings.TrimSpace(stderr); stderr != "" {
+				message += ": " + stderr
+			}
+
+			return execHookResult{phase: replicationv1alpha1.HookFailed, message: message}
+		}
+	}
+
+	return execHookResult{
+		phase:   replicationv1alpha1.HookSucceeded,
+		message: fmt.Sprintf("command succeeded in %d pods", len(pods)),
+	}
+}
+
+// runJobHook creates the Job of the hook, or checks on it if it was already
+// created. The Job is owned by the VolumeReplication.
+func (r *VolumeReplicationReconciler) runJobHook(
+	ctx context.Context,
+	logger logr.Logger,
+	instance *replicationv1alpha1.VolumeReplication,
+	hookType replicationv1alpha1.HookType,
+	hook *replicationv1alpha1.TransitionHook,
+	status *replicationv1alpha1.HookStatus,
+) {
+	timeout := getHookTimeout(hook)
+	name := getHookJobName(instance, hookType, hook.Name)
+
+	job := &batchv1.Job{}
+
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: instance.Namespace}, job)
+	if apierrors.IsNotFound(err) {
+		err = r.createHookJob(ctx, instance, hook, name, timeout)
+		if err != nil {
+			logger.Error(err, "failed to create job of hook", "HookName", hook.Name, "JobName", name)
+			status.Message = err.Error()
+
+			return
+		}
+
+		logger.Info("created job of hook", "HookName", hook.Name, "JobName", name)
+		status.Message = fmt.Sprintf("job/%s created", name)
+
+		return
+	}
+
+	if err != nil {
+		logger.Error(err, "failed to get job of hook", "HookName", hook.Name, "JobName", name)
+		status.Message = err.Error()
+
+		return
+	}
+
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+
+		switch condition.Type {
+		case batchv1.JobComplete:
+			completeHook(status, replicationv1alpha1.HookSucceeded, fmt.Sprintf("job/%s completed", name))
+
+			return
+		case batchv1.JobFailed:
+			completeHook(status, replicationv1alpha1.HookFailed, fmt.Sprintf("job/%s failed: %s", name, condition.Message))
+
+			return
+		}
+	}
+
+	if status.StartTime != nil && time.Since(status.StartTime.Time) > timeout {
+		completeHook(status, replicationv1alpha1.HookFailed, fmt.Sprintf("job/%s timed out after %s", name, timeout))
+
+		err = r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+		if err != nil && !apierrors.IsNotFound(err) {
+			logger.Error(err, "failed to delete job of hook", "HookName", hook.Name, "JobName", name)
+		}
+	}
+}
+
+// createHookJob creates the Job of the hook from its template.
+func (r *VolumeReplicationReconciler) createHookJob(
+	ctx context.Context,
+	instance *replicationv1alpha1.VolumeReplication,
+	hook *replicationv1alpha1.TransitionHook,
+	name string,
+	timeout time.Duration,
+) error {
+	template := hook.Job.Template.DeepCopy()
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   instance.Namespace,
+			Labels:      template.Labels,
+			Annotations: template.Annotations,
+		},
+		Spec: template.Spec,
+	}
+
+	if job.Spec.ActiveDeadlineSeconds == nil {
+		deadline := int64(timeout.Seconds())
+		job.Spec.ActiveDeadlineSeconds = &deadline
+	}
+
+	err := controllerutil.SetControllerReference(instance, job, r.Scheme)
+	if err != nil {
+		return err
+	}
+
+	return r.Create(ctx, job)
+}
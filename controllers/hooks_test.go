@@ -0,0 +1,311 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+	"github.com/csi-addons/volume-replication-operator/pkg/client/fake"
+
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// fakePodExecutor records the commands and fails them with err. The commands
+// wait for release to be closed if it is set.
+type fakePodExecutor struct {
+	mu       sync.Mutex
+	commands []string
+	stderr   string
+	err      error
+	release  chan struct{}
+}
+
+func (e *fakePodExecutor) Exec(_ context.Context, pod *corev1.Pod, container string, command []string) (string, string, error) {
+	if e.release != nil {
+		<-e.release
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.commands = append(e.commands, pod.Name+"/"+container+": "+strings.Join(command, " "))
+
+	return "", e.stderr, e.err
+}
+
+var mockHookPod = &corev1.Pod{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:      "db-0",
+		Namespace: mockNamespace,
+		Labels:    map[string]string{"app": "db"},
+	},
+	Spec: corev1.PodSpec{
+		Containers: []corev1.Container{{Name: "postgres"}},
+	},
+	Status: corev1.PodStatus{Phase: corev1.PodRunning},
+}
+
+func TestGetHookJobName(t *testing.T) {
+	t.Parallel()
+
+	instance := mockVolumeReplicationObj.DeepCopy()
+	instance.Name = strings.Repeat("a", 100)
+	instance.Generation = 1
+
+	name := getHookJobName(instance, replicationv1alpha1.PostPromoteHook, "Start_Recovery")
+	require.LessOrEqual(t, len(name), 63)
+	require.NotContains(t, name, "_")
+
+	instance.Generation = 2
+	require.NotEqual(t, name, getHookJobName(instance, replicationv1alpha1.PostPromoteHook, "Start_Recovery"))
+}
+
+func TestReconcilePreDemoteExecHook(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		name          string
+		execErr       error
+		failurePolicy replicationv1alpha1.HookFailurePolicy
+		wantDemoted   bool
+		wantPhase     replicationv1alpha1.HookPhase
+	}{
+		{
+			name:        "hook succeeds",
+			wantDemoted: true,
+			wantPhase:   replicationv1alpha1.HookSucceeded,
+		},
+		{
+			name:      "failed hook stops the demotion",
+			execErr:   errors.New("command terminated with exit code 1"),
+			wantPhase: replicationv1alpha1.HookFailed,
+		},
+		{
+			name:          "failed hook is ignored",
+			execErr:       errors.New("command terminated with exit code 1"),
+			failurePolicy: replicationv1alpha1.HookFailurePolicyIgnore,
+			wantDemoted:   true,
+			wantPhase:     replicationv1alpha1.HookFailed,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			volumeReplication := mockVolumeReplicationObj.DeepCopy()
+			volumeReplication.Spec.DataSource.Kind = pvcDataSource
+			volumeReplication.Spec.ReplicationState = replicationv1alpha1.Secondary
+			volumeReplication.Status.State = replicationv1alpha1.PrimaryState
+
+			volumeReplicationClass := mockVolumeReplicationClassObj.DeepCopy()
+			volumeReplicationClass.Spec.Hooks = &replicationv1alpha1.TransitionHooks{
+				PreDemote: []replicationv1alpha1.TransitionHook{{
+					Name: "freeze",
+					Exec: &replicationv1alpha1.ExecHook{
+						Selector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+						Command:  []string{"fsfreeze", "-f", "/data"},
+					},
+					FailurePolicy: tc.failurePolicy,
+				}},
+			}
+
+			reconciler := createFakeVolumeReplicationReconciler(t,
+				volumeReplication,
+				mockHookPod.DeepCopy(),
+				volumeReplicationClass,
+				mockPersistentVolumeClaim.DeepCopy(),
+				mockPersistentVolume.DeepCopy(),
+			)
+			driver := fake.NewStatefulReplicationClient(1)
+			executor := &fakePodExecutor{err: tc.execErr, stderr: "permission denied"}
+			reconciler.Replication = driver
+			reconciler.podExec = executor
+
+			key := types.NamespacedName{Name: volumeReplication.Name, Namespace: volumeReplication.Namespace}
+
+			_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+			require.NoError(t, err)
+			require.Equal(t, []string{"db-0/postgres: fsfreeze -f /data"}, executor.commands)
+			require.Equal(t, tc.wantDemoted, slices.Contains(driver.Calls(), fake.DemoteVolume))
+
+			latest := &replicationv1alpha1.VolumeReplication{}
+			require.NoError(t, reconciler.Get(context.TODO(), key, latest))
+			require.Len(t, latest.Status.Hooks, 1)
+			require.Equal(t, tc.wantPhase, latest.Status.Hooks[0].Phase)
+			require.Equal(t, replicationv1alpha1.PreDemoteHook, latest.Status.Hooks[0].Type)
+
+			hookFailed := findCondition(latest.Status.Conditions, ConditionHookFailed)
+			if tc.wantDemoted {
+				require.Nil(t, hookFailed)
+				require.Equal(t, replicationv1alpha1.SecondaryState, latest.Status.State)
+			} else {
+				require.NotNil(t, hookFailed)
+				require.Equal(t, PreDemoteHookFailed, hookFailed.Reason)
+				require.Contains(t, hookFailed.Message, "permission denied")
+				require.Equal(t, replicationv1alpha1.PrimaryState, latest.Status.State)
+			}
+		})
+	}
+}
+
+func TestReconcileExecHookInBackground(t *testing.T) {
+	t.Parallel()
+
+	volumeReplication := mockVolumeReplicationObj.DeepCopy()
+	volumeReplication.Spec.DataSource.Kind = pvcDataSource
+	volumeReplication.Spec.ReplicationState = replicationv1alpha1.Secondary
+	volumeReplication.Status.State = replicationv1alpha1.PrimaryState
+
+	volumeReplicationClass := mockVolumeReplicationClassObj.DeepCopy()
+	volumeReplicationClass.Spec.Hooks = &replicationv1alpha1.TransitionHooks{
+		PreDemote: []replicationv1alpha1.TransitionHook{{
+			Name: "freeze",
+			Exec: &replicationv1alpha1.ExecHook{
+				Selector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+				Command:  []string{"fsfreeze", "-f", "/data"},
+			},
+		}},
+	}
+
+	reconciler := createFakeVolumeReplicationReconciler(t,
+		volumeReplication,
+		mockHookPod.DeepCopy(),
+		volumeReplicationClass,
+		mockPersistentVolumeClaim.DeepCopy(),
+		mockPersistentVolume.DeepCopy(),
+	)
+	driver := fake.NewStatefulReplicationClient(1)
+	executor := &fakePodExecutor{release: make(chan struct{})}
+	reconciler.Replication = driver
+	reconciler.podExec = executor
+	reconciler.execHooks = newExecHookRunner()
+
+	key := types.NamespacedName{Name: volumeReplication.Name, Namespace: volumeReplication.Namespace}
+
+	// the reconcile does not wait for the command
+	result, err := reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	require.Equal(t, hookPollInterval, result.RequeueAfter)
+	require.NotContains(t, driver.Calls(), fake.DemoteVolume)
+
+	latest := &replicationv1alpha1.VolumeReplication{}
+	require.NoError(t, reconciler.Get(context.TODO(), key, latest))
+	require.Len(t, latest.Status.Hooks, 1)
+	require.Equal(t, replicationv1alpha1.HookRunning, latest.Status.Hooks[0].Phase)
+
+	// the command is not started again while it is running
+	_, err = reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	close(executor.release)
+	require.Eventually(t, func() bool {
+		reconciler.execHooks.mu.Lock()
+		defer reconciler.execHooks.mu.Unlock()
+
+		return reconciler.execHooks.results[getExecHookKey(latest, replicationv1alpha1.PreDemoteHook, "freeze")] != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	_, err = reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	require.Len(t, executor.commands, 1)
+	require.Contains(t, driver.Calls(), fake.DemoteVolume)
+
+	require.NoError(t, reconciler.Get(context.TODO(), key, latest))
+	require.Equal(t, replicationv1alpha1.HookSucceeded, latest.Status.Hooks[0].Phase)
+	require.Equal(t, replicationv1alpha1.SecondaryState, latest.Status.State)
+}
+
+func TestReconcilePostPromoteJobHook(t *testing.T) {
+	t.Parallel()
+
+	volumeReplication := mockVolumeReplicationObj.DeepCopy()
+	volumeReplication.Spec.DataSource.Kind = pvcDataSource
+	volumeReplication.Spec.ReplicationState = replicationv1alpha1.Primary
+	volumeReplication.Status.State = replicationv1alpha1.SecondaryState
+
+	volumeReplicationClass := mockVolumeReplicationClassObj.DeepCopy()
+	volumeReplicationClass.Spec.Hooks = &replicationv1alpha1.TransitionHooks{
+		PostPromote: []replicationv1alpha1.TransitionHook{{
+			Name: "recover",
+			Job: &replicationv1alpha1.JobHook{
+				Template: batchv1.JobTemplateSpec{
+					Spec: batchv1.JobSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								RestartPolicy: corev1.RestartPolicyNever,
+								Containers:    []corev1.Container{{Name: "recover", Image: "db-tools"}},
+							},
+						},
+					},
+				},
+			},
+		}},
+	}
+
+	reconciler := createFakeVolumeReplicationReconciler(t,
+		volumeReplication,
+		volumeReplicationClass,
+		mockPersistentVolumeClaim.DeepCopy(),
+		mockPersistentVolume.DeepCopy(),
+	)
+	reconciler.Replication = fake.NewStatefulReplicationClient(1)
+
+	key := types.NamespacedName{Name: volumeReplication.Name, Namespace: volumeReplication.Namespace}
+
+	result, err := reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	require.Equal(t, hookPollInterval, result.RequeueAfter)
+
+	latest := &replicationv1alpha1.VolumeReplication{}
+	require.NoError(t, reconciler.Get(context.TODO(), key, latest))
+	require.Equal(t, replicationv1alpha1.PrimaryState, latest.Status.State)
+	require.Nil(t, latest.Status.LastCompletionTime)
+	require.Len(t, latest.Status.Hooks, 1)
+	require.Equal(t, replicationv1alpha1.HookRunning, latest.Status.Hooks[0].Phase)
+
+	job := &batchv1.Job{}
+	jobKey := types.NamespacedName{
+		Name:      getHookJobName(latest, replicationv1alpha1.PostPromoteHook, "recover"),
+		Namespace: mockNamespace,
+	}
+	require.NoError(t, reconciler.Get(context.TODO(), jobKey, job))
+	require.Equal(t, latest.Name, job.OwnerReferences[0].Name)
+	require.NotNil(t, job.Spec.ActiveDeadlineSeconds)
+
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	require.NoError(t, reconciler.Status().Update(context.TODO(), job))
+
+	_, err = reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	require.NoError(t, reconciler.Get(context.TODO(), key, latest))
+	require.Equal(t, replicationv1alpha1.HookSucceeded, latest.Status.Hooks[0].Phase)
+	require.NotNil(t, latest.Status.LastCompletionTime)
+	require.Equal(t, "volume is marked primary", latest.Status.Message)
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	volumegroupv1 "github.com/IBM/csi-volume-group-operator/api/v1"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// getVGMembers builds the member list of the VolumeReplication from the
+// PVCs reported in the VolumeGroup status.
+func (r *VolumeReplicationReconciler) getVGMembers(ctx context.Context, logger logr.Logger, vg *volumegroupv1.VolumeGroup) (
+	[]replicationv1alpha1.VolumeReplicationMember, error,
+) {
+	members := make([]replicationv1alpha1.VolumeReplicationMember, 0, len(vg.Status.PVCList))
+
+	for i := range vg.Status.PVCList {
+		pvcName := vg.Status.PVCList[i].Name
+
+		member, err := r.getMember(ctx, logger, types.NamespacedName{Name: pvcName, Namespace: vg.Namespace})
+		if err != nil {
+			return nil, err
+		}
+
+		members = append(members, member)
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].PVCName < members[j].PVCName
+	})
+
+	return members, nil
+}
+
+// getMember returns the member entry for a single PVC. A PVC or PV that does
+// not exist (anymore) is reported as not bound.
+func (r *VolumeReplicationReconciler) getMember(ctx context.Context, logger logr.Logger, req types.NamespacedName) (
+	replicationv1alpha1.VolumeReplicationMember, error,
+) {
+	member := replicationv1alpha1.VolumeReplicationMember{PVCName: req.Name}
+
+	pvc := &corev1.PersistentVolumeClaim{}
+
+	err := r.Get(ctx, req, pvc)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("VolumeGroup member PVC not found", "PVC Name", req.Name)
+
+			return member, nil
+		}
+
+		return member, err
+	}
+
+	if pvc.Status.Phase != corev1.ClaimBound || pvc.Spec.VolumeName == "" {
+		return member, nil
+	}
+
+	pv := &corev1.PersistentVolume{}
+
+	err = r.Get(ctx, types.NamespacedName{Name: pvc.Spec.VolumeName}, pv)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("VolumeGroup member PV not found", "PV Name", pvc.Spec.VolumeName)
+
+			return member, nil
+		}
+
+		return member, err
+	}
+
+	member.Bound = true
+	if pv.Spec.CSI != nil {
+		member.VolumeHandle = pv.Spec.CSI.VolumeHandle
+	}
+
+	return member, nil
+}
+
+// updateVGMembers refreshes status.members of the VolumeReplication and
+// reports any change in the group membership as an event and condition.
+func (r *VolumeReplicationReconciler) updateVGMembers(ctx context.Context, logger logr.Logger,
+	instance *replicationv1alpha1.VolumeReplication, vg *volumegroupv1.VolumeGroup,
+) error {
+	members, err := r.getVGMembers(ctx, logger, vg)
+	if err != nil {
+		logger.Error(err, "failed to get VolumeGroup members", "VGName", vg.Name)
+
+		return err
+	}
+
+	added, removed := diffMembers(instance.Status.Members, members)
+
+	// an empty member list is not stored, so the condition marks the members
+	// as recorded. A change is reported once, the condition is cleared by
+	// the next reconcile.
+	recorded := findCondition(instance.Status.Conditions, ConditionMembershipChanged) != nil
+	if recorded && (len(added) != 0 || len(removed) != 0) {
+		msg := membershipChangeMessage(added, removed)
+		logger.Info("VolumeGroup membership changed", "VGName", vg.Name, "Added", added, "Removed", removed)
+		setMembershipChangedCondition(&instance.Status.Conditions, instance.Generation, msg)
+		r.recordEvent(instance, corev1.EventTypeNormal, MembershipChanged, msg)
+	} else {
+		setMembershipUnchangedCondition(&instance.Status.Conditions, instance.Generation)
+	}
+
+	instance.Status.Members = members
+
+	return nil
+}
+
+// diffMembers returns the PVC names that were added to and removed from the
+// member list.
+func diffMembers(oldMembers, newMembers []replicationv1alpha1.VolumeReplicationMember) ([]string, []string) {
+	oldNames := make(map[string]bool, len(oldMembers))
+	for _, m := range oldMembers {
+		oldNames[m.PVCName] = true
+	}
+
+	newNames := make(map[string]bool, len(newMembers))
+	for _, m := range newMembers {
+		newNames[m.PVCName] = true
+	}
+
+	var added, removed []string
+
+	for _, m := range newMembers {
+		if !oldNames[m.PVCName] {
+			added = append(added, m.PVCName)
+		}
+	}
+
+	for _, m := range oldMembers {
+		if !newNames[m.PVCName] {
+			removed = append(removed, m.PVCName)
+		}
+	}
+
+	return added, removed
+}
+
+func membershipChangeMessage(added, removed []string) string {
+	var parts []string
+
+	if len(added) != 0 {
+		parts = append(parts, fmt.Sprintf("added %s", strings.Join(added, ",")))
+	}
+
+	if len(removed) != 0 {
+		parts = append(parts, fmt.Sprintf("removed %s", strings.Join(removed, ",")))
+	}
+
+	return "VolumeGroup members " + strings.Join(parts, "; ")
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDiffMembers(t *testing.T) {
+	t.Parallel()
+
+	oldMembers := []replicationv1alpha1.VolumeReplicationMember{{PVCName: "a"}, {PVCName: "b"}}
+	newMembers := []replicationv1alpha1.VolumeReplicationMember{{PVCName: "b"}, {PVCName: "c"}}
+
+	added, removed := diffMembers(oldMembers, newMembers)
+	require.Equal(t, []string{"c"}, added)
+	require.Equal(t, []string{"a"}, removed)
+
+	added, removed = diffMembers(newMembers, newMembers)
+	require.Empty(t, added)
+	require.Empty(t, removed)
+}
+
+func TestUpdateVGMembers(t *testing.T) {
+	t.Parallel()
+
+	contentName := mockVGCName
+	ready := true
+	vg := newMockVolumeGroup(nil, &contentName, &ready)
+	vg.Status.PVCList = []corev1.PersistentVolumeClaim{
+		{ObjectMeta: metav1.ObjectMeta{Name: mockPVCName, Namespace: mockNamespace}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pending-pvc", Namespace: mockNamespace}},
+	}
+
+	pendingPVC := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "pending-pvc", Namespace: mockNamespace},
+		Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+	}
+
+	volumeReplication := &replicationv1alpha1.VolumeReplication{}
+	mockVolumeReplicationObj.DeepCopyInto(volumeReplication)
+
+	reconciler := createFakeVolumeReplicationReconciler(t, mockPersistentVolume.DeepCopy(),
+		mockPersistentVolumeClaim.DeepCopy(), pendingPVC, vg)
+
+	err := reconciler.updateVGMembers(context.TODO(), reconciler.Log, volumeReplication, vg)
+	require.NoError(t, err)
+	require.Equal(t, []replicationv1alpha1.VolumeReplicationMember{
+		{PVCName: "pending-pvc"},
+		{PVCName: mockPVCName, VolumeHandle: mockVolumeHandle, Bound: true},
+	}, volumeReplication.Status.Members)
+
+	condition := findCondition(volumeReplication.Status.Conditions, ConditionMembershipChanged)
+	require.NotNil(t, condition)
+	require.Equal(t, metav1.ConditionFalse, condition.Status)
+
+	vg.Status.PVCList = vg.Status.PVCList[:1]
+
+	err = reconciler.updateVGMembers(context.TODO(), reconciler.Log, volumeReplication, vg)
+	require.NoError(t, err)
+	require.Len(t, volumeReplication.Status.Members, 1)
+
+	condition = findCondition(volumeReplication.Status.Conditions, ConditionMembershipChanged)
+	require.NotNil(t, condition)
+	require.Equal(t, metav1.ConditionTrue, condition.Status)
+	require.Contains(t, condition.Message, "removed pending-pvc")
+
+	// the change is only reported once
+	err = reconciler.updateVGMembers(context.TODO(), reconciler.Log, volumeReplication, vg)
+	require.NoError(t, err)
+
+	condition = findCondition(volumeReplication.Status.Conditions, ConditionMembershipChanged)
+	require.NotNil(t, condition)
+	require.Equal(t, metav1.ConditionFalse, condition.Status)
+}
+
+func TestUpdateVGMembersOfEmptyGroup(t *testing.T) {
+	t.Parallel()
+
+	contentName := mockVGCName
+	ready := true
+	vg := newMockVolumeGroup(nil, &contentName, &ready)
+
+	volumeReplication := mockVolumeReplicationObj.DeepCopy()
+
+	reconciler := createFakeVolumeReplicationReconciler(t, mockPersistentVolume.DeepCopy(),
+		mockPersistentVolumeClaim.DeepCopy(), vg)
+
+	err := reconciler.updateVGMembers(context.TODO(), reconciler.Log, volumeReplication, vg)
+	require.NoError(t, err)
+	require.Empty(t, volumeReplication.Status.Members)
+
+	// the empty member list reads back as nil
+	volumeReplication.Status.Members = nil
+	vg.Status.PVCList = []corev1.PersistentVolumeClaim{
+		{ObjectMeta: metav1.ObjectMeta{Name: mockPVCName, Namespace: mockNamespace}},
+	}
+
+	err = reconciler.updateVGMembers(context.TODO(), reconciler.Log, volumeReplication, vg)
+	require.NoError(t, err)
+
+	condition := findCondition(volumeReplication.Status.Conditions, ConditionMembershipChanged)
+	require.NotNil(t, condition)
+	require.Equal(t, metav1.ConditionTrue, condition.Status)
+	require.Contains(t, condition.Message, "added "+mockPVCName)
+}
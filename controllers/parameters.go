@@ -31,6 +31,12 @@ const (
 
 	prefixedReplicationSecretNameKey      = replicationParameterPrefix + "replication-secret-name"      // name key for secret
 	prefixedReplicationSecretNamespaceKey = replicationParameterPrefix + "replication-secret-namespace" // namespace key secret
+
+	prefixedDemotionRequeueIntervalKey       = replicationParameterPrefix + "demotion-requeue-interval"        // requeue interval after first demotion
+	prefixedSecondaryErrorRequeueIntervalKey = replicationParameterPrefix + "secondary-error-requeue-interval" // requeue interval on secondary errors
+	prefixedResyncRequeueIntervalKey         = replicationParameterPrefix + "resync-requeue-interval"          // requeue interval while resyncing
+	prefixedMaxRequeueIntervalKey            = replicationParameterPrefix + "max-requeue-interval"             // cap of the requeue backoff
+	prefixedRequeueJitterKey                 = replicationParameterPrefix + "requeue-jitter"                   // jitter factor of the requeue intervals
 )
 
 // filterPrefixedParameters removes all the reserved keys from the
@@ -61,6 +67,12 @@ func validatePrefixedParameters(param map[string]string) error {
 				if val == "" {
 					return errors.New("secret namespace cannot be empty")
 				}
+			case prefixedDemotionRequeueIntervalKey, prefixedSecondaryErrorRequeueIntervalKey, prefixedResyncRequeueIntervalKey,
+				prefixedMaxRequeueIntervalKey, prefixedRequeueJitterKey:
+				_, err := parseRequeueConfig(map[string]string{key: val})
+				if err != nil {
+					return err
+				}
 			// keep adding known prefixes to this list.
 			default:
 				return fmt.Errorf("found unknown parameter key %q with reserved prefix %s", key, replicationParameterPrefix)
@@ -0,0 +1,84 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// getPauseReason returns the condition reason and message if the
+// reconciliation of the VolumeReplication is paused, either on the
+// VolumeReplication itself or on its class. The deletion is paused as well,
+// as replication has to be disabled before the finalizer is removed.
+func getPauseReason(instance *replicationv1alpha1.VolumeReplication, vrc *replicationv1alpha1.VolumeReplicationClass) (string, string, bool) {
+	var reason, msg string
+
+	switch {
+	case instance.Spec.Paused:
+		reason, msg = PausedByVolumeReplication, "reconciliation is paused on the VolumeReplication"
+	case vrc.Spec.Paused:
+		reason, msg = PausedByVolumeReplicationClass,
+			fmt.Sprintf("reconciliation is paused on the VolumeReplicationClass %q", vrc.Name)
+	default:
+		return "", "", false
+	}
+
+	if !instance.GetDeletionTimestamp().IsZero() {
+		return DeletionPaused, msg + ", the deletion waits for it to be resumed to disable replication", true
+	}
+
+	return reason, msg, true
+}
+
+// volumeReplicationsForClass maps a VolumeReplicationClass to the
+// VolumeReplications using it, or that have no class yet.
+func (r *VolumeReplicationReconciler) volumeReplicationsForClass(ctx context.Context, obj client.Object) []reconcile.Request {
+	vrList := &replicationv1alpha1.VolumeReplicationList{}
+
+	err := r.List(ctx, vrList)
+	if err != nil {
+		r.Log.Error(err, "failed to list volumeReplications", "VRCName", obj.GetName())
+
+		return nil
+	}
+
+	var requests []reconcile.Request
+
+	for i := range vrList.Items {
+		vr := &vrList.Items[i]
+
+		// the VolumeReplications without a class yet might choose this one
+		vrcName := getRecordedClassName(vr)
+
+		if vrcName != "" && vrcName != obj.GetName() {
+			continue
+		}
+
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: vr.Name, Namespace: vr.Namespace},
+		})
+	}
+
+	return requests
+}
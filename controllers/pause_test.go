@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetPauseReason(t *testing.T) {
+	t.Parallel()
+
+	volumeReplication := mockVolumeReplicationObj.DeepCopy()
+	volumeReplicationClass := mockVolumeReplicationClassObj.DeepCopy()
+
+	_, _, paused := getPauseReason(volumeReplication, volumeReplicationClass)
+	require.False(t, paused)
+
+	volumeReplicationClass.Spec.Paused = true
+	reason, _, paused := getPauseReason(volumeReplication, volumeReplicationClass)
+	require.True(t, paused)
+	require.Equal(t, PausedByVolumeReplicationClass, reason)
+
+	volumeReplication.Spec.Paused = true
+	reason, _, paused = getPauseReason(volumeReplication, volumeReplicationClass)
+	require.True(t, paused)
+	require.Equal(t, PausedByVolumeReplication, reason)
+
+	// the deletion is held with its own reason
+	volumeReplication.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	reason, msg, paused := getPauseReason(volumeReplication, volumeReplicationClass)
+	require.True(t, paused)
+	require.Equal(t, DeletionPaused, reason)
+	require.Contains(t, msg, "deletion waits")
+}
+
+func TestVolumeReplicationsForClass(t *testing.T) {
+	t.Parallel()
+
+	volumeReplication := mockVolumeReplicationObj.DeepCopy()
+	otherVolumeReplication := mockVolumeReplicationObj.DeepCopy()
+	otherVolumeReplication.Name = "other-volume-replication"
+	otherVolumeReplication.Spec.VolumeReplicationClass = "other-class"
+
+	reconciler := createFakeVolumeReplicationReconciler(t, volumeReplication, otherVolumeReplication)
+
+	requests := reconciler.volumeReplicationsForClass(context.TODO(), mockVolumeReplicationClassObj.DeepCopy())
+	require.Len(t, requests, 1)
+	require.Equal(t, volumeReplication.Name, requests[0].Name)
+}
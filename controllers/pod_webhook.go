@@ -0,0 +1,144 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+	"github.com/csi-addons/volume-replication-operator/pkg/config"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// volumeReplicationPVCIndex indexes VolumeReplications by the names of the
+// claims they replicate.
+const volumeReplicationPVCIndex = "volumeReplication.pvcName"
+
+// volumeReplicationPVCIndexFunc returns the names of the claims replicated by
+// the VolumeReplication.
+func volumeReplicationPVCIndexFunc(obj client.Object) []string {
+	vr, ok := obj.(*replicationv1alpha1.VolumeReplication)
+	if !ok {
+		return nil
+	}
+
+	return getSourcePVCNames(vr)
+}
+
+// +kubebuilder:webhook:path=/validate--v1-pod,mutating=false,failurePolicy=ignore,sideEffects=None,groups="",resources=pods,verbs=create,versions=v1,name=vpod.replication.storage.openshift.io,admissionReviewVersions=v1
+
+// podValidator checks that the pods only mount claims that are primary, as
+// mounting a secondary volume fails on the node.
+type podValidator struct {
+	client.Reader
+
+	log  logr.Logger
+	mode string
+}
+
+var _ admission.CustomValidator = &podValidator{}
+
+// setupPodWebhook registers the pod validating webhook in the given mode.
+func setupPodWebhook(mgr ctrl.Manager, log logr.Logger, mode string) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&corev1.Pod{}).
+		WithValidator(&podValidator{Reader: mgr.GetClient(), log: log, mode: mode}).
+		Complete()
+}
+
+// getInactiveReason returns why the volume replicated by the VolumeReplication
+// cannot be mounted, or an empty string if it can.
+func getInactiveReason(instance *replicationv1alpha1.VolumeReplication) string {
+	resyncing := findCondition(instance.Status.Conditions, ConditionResyncing)
+	if resyncing != nil && resyncing.Status == metav1.ConditionTrue {
+		return "resyncing"
+	}
+
+	if instance.Status.State == replicationv1alpha1.SecondaryState {
+		return "secondary"
+	}
+
+	return ""
+}
+
+// ValidateCreate rejects, or warns about, a pod mounting claims that are
+// secondary or resyncing.
+func (v *podValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return nil, fmt.Errorf("expected a pod but got %T", obj)
+	}
+
+	namespace := pod.Namespace
+	if namespace == "" {
+		req, err := admission.RequestFromContext(ctx)
+		if err == nil {
+			namespace = req.Namespace
+		}
+	}
+
+	var problems []string
+
+	for _, pvcName := range podPVCIndexFunc(pod) {
+		vrList := &replicationv1alpha1.VolumeReplicationList{}
+
+		err := v.List(ctx, vrList, client.InNamespace(namespace), client.MatchingFields{volumeReplicationPVCIndex: pvcName})
+		if err != nil {
+			// the webhook fails open, as does its failure policy.
+			v.log.Error(err, "failed to list volumeReplications", "PVCName", pvcName, "Namespace", namespace)
+
+			continue
+		}
+
+		for i := range vrList.Items {
+			reason := getInactiveReason(&vrList.Items[i])
+			if reason != "" {
+				problems = append(problems, fmt.Sprintf("persistentVolumeClaim %q is %s as replicated by volumeReplication %q",
+					pvcName, reason, vrList.Items[i].Name))
+			}
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil, nil
+	}
+
+	if v.mode == config.PodAdmissionWarn {
+		return problems, nil
+	}
+
+	return nil, fmt.Errorf("pod mounts volumes that are not primary: %s", strings.Join(problems, "; "))
+}
+
+// ValidateUpdate admits all updates, as the volumes of a pod are immutable.
+func (v *podValidator) ValidateUpdate(context.Context, runtime.Object, runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// ValidateDelete admits all deletions.
+func (v *podValidator) ValidateDelete(context.Context, runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+	"github.com/csi-addons/volume-replication-operator/pkg/config"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func TestPodValidatorValidateCreate(t *testing.T) {
+	t.Parallel()
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-0", Namespace: mockNamespace},
+		Spec: corev1.PodSpec{
+			Volumes: []corev1.Volume{{
+				Name: "data",
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: mockPVCName},
+				},
+			}},
+		},
+	}
+
+	resyncing := []metav1.Condition{{
+		Type:   ConditionResyncing,
+		Reason: ResyncTriggered,
+		Status: metav1.ConditionTrue,
+	}}
+
+	testcases := []struct {
+		name       string
+		state      replicationv1alpha1.State
+		conditions []metav1.Condition
+		mode       string
+		noVR       bool
+		wantErr    string
+		wantWarn   string
+	}{
+		{
+			name:  "primary claim is admitted",
+			state: replicationv1alpha1.PrimaryState,
+			mode:  config.PodAdmissionDeny,
+		},
+		{
+			name: "claim without volumeReplication is admitted",
+			mode: config.PodAdmissionDeny,
+			noVR: true,
+		},
+		{
+			name:    "secondary claim is rejected",
+			state:   replicationv1alpha1.SecondaryState,
+			mode:    config.PodAdmissionDeny,
+			wantErr: `persistentVolumeClaim "test-pvc" is secondary as replicated by volumeReplication "volume-replication"`,
+		},
+		{
+			name:       "resyncing claim is rejected",
+			state:      replicationv1alpha1.SecondaryState,
+			conditions: resyncing,
+			mode:       config.PodAdmissionDeny,
+			wantErr:    `persistentVolumeClaim "test-pvc" is resyncing`,
+		},
+		{
+			name:     "secondary claim is admitted with a warning",
+			state:    replicationv1alpha1.SecondaryState,
+			mode:     config.PodAdmissionWarn,
+			wantWarn: `persistentVolumeClaim "test-pvc" is secondary as replicated by volumeReplication "volume-replication"`,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			volumeReplication := mockVolumeReplicationObj.DeepCopy()
+			volumeReplication.Spec.DataSource.Kind = pvcDataSource
+			volumeReplication.Status.State = tc.state
+			volumeReplication.Status.Conditions = tc.conditions
+
+			objs := []runtime.Object{mockPersistentVolumeClaim.DeepCopy()}
+			if !tc.noVR {
+				objs = append(objs, volumeReplication)
+			}
+
+			reconciler := createFakeVolumeReplicationReconciler(t, objs...)
+			validator := &podValidator{Reader: reconciler.Client, log: logf.Log, mode: tc.mode}
+
+			warnings, err := validator.ValidateCreate(context.TODO(), pod.DeepCopy())
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			if tc.wantWarn != "" {
+				require.Len(t, warnings, 1)
+				require.Contains(t, warnings[0], tc.wantWarn)
+			} else {
+				require.Empty(t, warnings)
+			}
+		})
+	}
+}
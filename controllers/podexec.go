@@ -0,0 +1,81 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// podExecutor runs commands in the containers of pods.
+type podExecutor interface {
+	// Exec runs the command in the container of the pod and returns its
+	// stdout and stderr.
+	Exec(ctx context.Context, pod *corev1.Pod, container string, command []string) (string, string, error)
+}
+
+// remotePodExecutor runs commands through the exec subresource of pods.
+type remotePodExecutor struct {
+	config    *rest.Config
+	clientset kubernetes.Interface
+}
+
+var _ podExecutor = &remotePodExecutor{}
+
+// newRemotePodExecutor returns a podExecutor using the given config.
+func newRemotePodExecutor(config *rest.Config) (*remotePodExecutor, error) {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &remotePodExecutor{config: config, clientset: clientset}, nil
+}
+
+func (e *remotePodExecutor) Exec(ctx context.Context, pod *corev1.Pod, container string, command []string) (string, string, error) {
+	req := e.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(e.config, "POST", req.URL())
+	if err != nil {
+		return "", "", err
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdout: &stdout,
+		Stderr: &stderr,
+	})
+
+	return stdout.String(), stderr.String(), err
+}
@@ -20,11 +20,16 @@ import (
 	"context"
 	"testing"
 
+	volumegroupv1 "github.com/IBM/csi-volume-group-operator/api/v1"
+
 	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
 	"github.com/csi-addons/volume-replication-operator/pkg/config"
 
 	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -91,11 +96,31 @@ func createFakeScheme(t *testing.T) *runtime.Scheme {
 		require.Fail(t, "failed to add corev1 scheme")
 	}
 
+	err = appsv1.AddToScheme(scheme)
+	if err != nil {
+		require.Fail(t, "failed to add appsv1 scheme")
+	}
+
+	err = batchv1.AddToScheme(scheme)
+	if err != nil {
+		require.Fail(t, "failed to add batchv1 scheme")
+	}
+
+	err = storagev1.AddToScheme(scheme)
+	if err != nil {
+		require.Fail(t, "failed to add storagev1 scheme")
+	}
+
 	err = replicationv1alpha1.AddToScheme(scheme)
 	if err != nil {
 		require.Fail(t, "failed to add replicationv1alpha1 scheme")
 	}
 
+	err = volumegroupv1.AddToScheme(scheme)
+	if err != nil {
+		require.Fail(t, "failed to add volumegroupv1 scheme")
+	}
+
 	return scheme
 }
 
@@ -103,7 +128,14 @@ func createFakeVolumeReplicationReconciler(t *testing.T, obj ...runtime.Object)
 	t.Helper()
 
 	scheme := createFakeScheme(t)
-	client := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(obj...).Build()
+	client := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(obj...).
+		WithStatusSubresource(&replicationv1alpha1.VolumeReplication{}, &replicationv1alpha1.VolumeReplicationOperation{},
+			&replicationv1alpha1.VolumeReplicationPolicy{}).
+		WithIndex(&corev1.Pod{}, podPVCIndex, podPVCIndexFunc).
+		WithIndex(&replicationv1alpha1.VolumeReplication{}, volumeReplicationPVCIndex, volumeReplicationPVCIndexFunc).
+		WithIndex(&replicationv1alpha1.VolumeReplication{}, volumeReplicationDependsOnIndex, volumeReplicationDependsOnIndexFunc).
+		WithIndex(&storagev1.VolumeAttachment{}, volumeAttachmentPVIndex, volumeAttachmentPVIndexFunc).
+		WithIndex(&corev1.PersistentVolumeClaim{}, pvcStorageClassIndex, pvcStorageClassIndexFunc).Build()
 
 	return VolumeReplicationReconciler{
 		Client:       client,
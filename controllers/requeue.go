@@ -0,0 +1,195 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// defaultDemotionRequeueInterval is the requeue interval after the volume
+	// is marked secondary for the first time. For some storage providers it
+	// takes some time to determine whether the volume needs correction.
+	defaultDemotionRequeueInterval = 15 * time.Second
+	// defaultSecondaryErrorRequeueInterval is the requeue interval after any
+	// error while the volume is secondary.
+	defaultSecondaryErrorRequeueInterval = 15 * time.Second
+	// defaultResyncRequeueInterval is the requeue interval while the volume
+	// is resyncing. The resync can take time and the default exponential
+	// backoff of the workqueue can affect the RTO.
+	defaultResyncRequeueInterval = 30 * time.Second
+)
+
+// requeueConfig holds the requeue intervals of a VolumeReplicationClass.
+type requeueConfig struct {
+	demotion       time.Duration
+	secondaryError time.Duration
+	resync         time.Duration
+	// maxInterval caps the exponential backoff of the intervals above. The
+	// intervals are not backed off if it is not larger than them.
+	maxInterval time.Duration
+	// jitter is the maximum factor of the interval added as random jitter.
+	jitter float64
+}
+
+// parseRequeueConfig returns the requeue configuration from the
+// VolumeReplicationClass parameters, falling back to the defaults.
+func parseRequeueConfig(param map[string]string) (requeueConfig, error) {
+	cfg := requeueConfig{
+		demotion:       defaultDemotionRequeueInterval,
+		secondaryError: defaultSecondaryErrorRequeueInterval,
+		resync:         defaultResyncRequeueInterval,
+	}
+
+	durations := map[string]*time.Duration{
+		prefixedDemotionRequeueIntervalKey:       &cfg.demotion,
+		prefixedSecondaryErrorRequeueIntervalKey: &cfg.secondaryError,
+		prefixedResyncRequeueIntervalKey:         &cfg.resync,
+		prefixedMaxRequeueIntervalKey:            &cfg.maxInterval,
+	}
+
+	for key, d := range durations {
+		val, ok := param[key]
+		if !ok {
+			continue
+		}
+
+		parsed, err := time.ParseDuration(val)
+		if err != nil || parsed <= 0 {
+			return cfg, fmt.Errorf("invalid duration %q for parameter %q", val, key)
+		}
+
+		*d = parsed
+	}
+
+	if val, ok := param[prefixedRequeueJitterKey]; ok {
+		jitter, err := strconv.ParseFloat(val, 64)
+		if err != nil || jitter < 0 || jitter > 1 {
+			return cfg, fmt.Errorf("invalid jitter %q for parameter %q, must be between 0 and 1", val, prefixedRequeueJitterKey)
+		}
+
+		cfg.jitter = jitter
+	}
+
+	return cfg, nil
+}
+
+// requeueBackoff tracks the consecutive requeues of each VolumeReplication
+// to back off the requeue intervals exponentially.
+type requeueBackoff struct {
+	mu       sync.Mutex
+	attempts map[string]int
+}
+
+func newRequeueBackoff() *requeueBackoff {
+	return &requeueBackoff{attempts: map[string]int{}}
+}
+
+// next returns the interval for the next requeue of the object. The base
+// interval is doubled for every consecutive requeue up to maxInterval, and
+// jitter is added on top.
+func (b *requeueBackoff) next(key string, base, maxInterval time.Duration, jitter float64) time.Duration {
+	interval := base
+
+	if b != nil && maxInterval > base {
+		b.mu.Lock()
+		attempts := b.attempts[key]
+		b.attempts[key] = attempts + 1
+		b.mu.Unlock()
+
+		for i := 0; i < attempts && interval < maxInterval; i++ {
+			interval *= 2
+		}
+
+		if interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+
+	if jitter > 0 {
+		interval = wait.Jitter(interval, jitter)
+	}
+
+	return interval
+}
+
+// reset forgets the consecutive requeues of the object.
+func (b *requeueBackoff) reset(key string) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.attempts, key)
+}
+
+// rpcBudget limits the rate of the low priority RPCs, like the ones issued
+// for polling secondary and resyncing volumes, so that they cannot starve
+// promotions which are not limited.
+type rpcBudget struct {
+	limiter *rate.Limiter
+}
+
+// newRPCBudget returns a budget allowing qps low priority RPCs per second
+// with the given burst. A non-positive qps disables the budget.
+func newRPCBudget(qps float64, burst int) *rpcBudget {
+	if qps <= 0 {
+		return nil
+	}
+
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &rpcBudget{limiter: rate.NewLimiter(rate.Limit(qps), burst)}
+}
+
+// reserve takes n RPCs from the budget. If the budget is exhausted nothing is
+// taken and the time to wait before trying again is returned.
+func (b *rpcBudget) reserve(n int) (bool, time.Duration) {
+	if b == nil {
+		return true, 0
+	}
+
+	if n > b.limiter.Burst() {
+		n = b.limiter.Burst()
+	}
+
+	now := time.Now()
+
+	reservation := b.limiter.ReserveN(now, n)
+	if !reservation.OK() {
+		return false, time.Second
+	}
+
+	delay := reservation.DelayFrom(now)
+	if delay > 0 {
+		reservation.CancelAt(now)
+
+		return false, delay
+	}
+
+	return true, 0
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestParseRequeueConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := parseRequeueConfig(map[string]string{})
+	require.NoError(t, err)
+	require.Equal(t, defaultDemotionRequeueInterval, cfg.demotion)
+	require.Equal(t, defaultSecondaryErrorRequeueInterval, cfg.secondaryError)
+	require.Equal(t, defaultResyncRequeueInterval, cfg.resync)
+
+	cfg, err = parseRequeueConfig(map[string]string{
+		prefixedResyncRequeueIntervalKey: "1m",
+		prefixedMaxRequeueIntervalKey:    "10m",
+		prefixedRequeueJitterKey:         "0.1",
+	})
+	require.NoError(t, err)
+	require.Equal(t, time.Minute, cfg.resync)
+	require.Equal(t, 10*time.Minute, cfg.maxInterval)
+	require.InDelta(t, 0.1, cfg.jitter, 0.0001)
+
+	_, err = parseRequeueConfig(map[string]string{prefixedResyncRequeueIntervalKey: "-1s"})
+	require.Error(t, err)
+
+	_, err = parseRequeueConfig(map[string]string{prefixedRequeueJitterKey: "2"})
+	require.Error(t, err)
+}
+
+func TestRequeueBackoff(t *testing.T) {
+	t.Parallel()
+
+	backoff := newRequeueBackoff()
+
+	// no backoff unless the cap is larger than the interval
+	require.Equal(t, 15*time.Second, backoff.next("a", 15*time.Second, 0, 0))
+	require.Equal(t, 15*time.Second, backoff.next("a", 15*time.Second, 0, 0))
+
+	require.Equal(t, 15*time.Second, backoff.next("b", 15*time.Second, time.Minute, 0))
+	require.Equal(t, 30*time.Second, backoff.next("b", 15*time.Second, time.Minute, 0))
+	require.Equal(t, time.Minute, backoff.next("b", 15*time.Second, time.Minute, 0))
+	require.Equal(t, time.Minute, backoff.next("b", 15*time.Second, time.Minute, 0))
+
+	backoff.reset("b")
+	require.Equal(t, 15*time.Second, backoff.next("b", 15*time.Second, time.Minute, 0))
+
+	jittered := backoff.next("c", 10*time.Second, 0, 0.5)
+	require.GreaterOrEqual(t, jittered, 10*time.Second)
+	require.LessOrEqual(t, jittered, 15*time.Second)
+
+	// a nil backoff only returns the base interval
+	var nilBackoff *requeueBackoff
+	require.Equal(t, 15*time.Second, nilBackoff.next("a", 15*time.Second, time.Minute, 0))
+}
+
+func TestRequeueBackoffResetOnDeletion(t *testing.T) {
+	t.Parallel()
+
+	volumeReplication := mockVolumeReplicationObj.DeepCopy()
+	volumeReplication.Finalizers = []string{volumeReplicationFinalizer}
+	volumeReplication.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+
+	reconciler := createFakeVolumeReplicationReconciler(t, volumeReplication)
+	reconciler.requeueBackoff = newRequeueBackoff()
+
+	ctx := context.TODO()
+	key := types.NamespacedName{Name: volumeReplication.Name, Namespace: volumeReplication.Namespace}
+
+	// the backoff is forgotten once the finalizer is removed
+	reconciler.requeueBackoff.next(key.String(), time.Second, time.Minute, 0)
+	require.NoError(t, reconciler.removeFinalizerFromVR(ctx, reconciler.Log, volumeReplication))
+	require.NotContains(t, reconciler.requeueBackoff.attempts, key.String())
+
+	// and when the VolumeReplication is not found
+	reconciler.requeueBackoff.next(key.String(), time.Second, time.Minute, 0)
+
+	_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	require.NotContains(t, reconciler.requeueBackoff.attempts, key.String())
+}
+
+func TestRPCBudget(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, newRPCBudget(0, 10))
+
+	var unlimited *rpcBudget
+
+	allowed, _ := unlimited.reserve(2)
+	require.True(t, allowed)
+
+	budget := newRPCBudget(0.001, 2)
+
+	allowed, _ = budget.reserve(2)
+	require.True(t, allowed)
+
+	allowed, delay := budget.reserve(1)
+	require.False(t, allowed)
+	require.Positive(t, delay)
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// roleLabel is set on the replicated claims to the current role of the
+	// volume, "primary", "secondary" or "unknown".
+	roleLabel = replicationParameterPrefix + "role"
+	// volumeReplicationAnnotation is set on the replicated claims to the
+	// name of the VolumeReplication.
+	volumeReplicationAnnotation = replicationParameterPrefix + "volume-replication"
+	// roleTransitionTimeAnnotation is set on the replicated claims to the
+	// time the role last changed.
+	roleTransitionTimeAnnotation = replicationParameterPrefix + "role-transition-time"
+)
+
+// getRoleLabelValue returns the role label value for the state.
+func getRoleLabelValue(state replicationv1alpha1.State) string {
+	if state == "" {
+		state = replicationv1alpha1.UnknownState
+	}
+
+	return strings.ToLower(string(state))
+}
+
+// claimRolesChanged returns true if the role or the replicated claims of the
+// instance differ from the stored ones, i.e. the claims need to be synced.
+func claimRolesChanged(stored, instance *replicationv1alpha1.VolumeReplication) bool {
+	return stored.Status.State != instance.Status.State ||
+		!slices.Equal(getSourcePVCNames(stored), getSourcePVCNames(instance))
+}
+
+// syncClaimRoles reflects the role of the volume onto the replicated claims,
+// and clears it from the claims no longer replicated by the VolumeReplication,
+// e.g. removed VolumeGroup members. All claims are cleared once the
+// VolumeReplication is deleted.
+func (r *VolumeReplicationReconciler) syncClaimRoles(
+	ctx context.Context,
+	logger logr.Logger,
+	instance *replicationv1alpha1.VolumeReplication,
+) error {
+	var pvcNames []string
+	if instance.GetDeletionTimestamp().IsZero() {
+		pvcNames = getSourcePVCNames(instance)
+	}
+
+	role := getRoleLabelValue(instance.Status.State)
+
+	for _, pvcName := range pvcNames {
+		pvc := &corev1.PersistentVolumeClaim{}
+
+		err := r.Get(ctx, types.NamespacedName{Name: pvcName, Namespace: instance.Namespace}, pvc)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		err = r.setClaimRole(ctx, logger, pvc, instance.Name, role)
+		if err != nil {
+			return err
+		}
+	}
+
+	pvcList := &corev1.PersistentVolumeClaimList{}
+
+	err := r.List(ctx, pvcList, client.InNamespace(instance.Namespace), client.HasLabels{roleLabel})
+	if err != nil {
+		return err
+	}
+
+	for i := range pvcList.Items {
+		pvc := &pvcList.Items[i]
+		if pvc.Annotations[volumeReplicationAnnotation] != instance.Name || slices.Contains(pvcNames, pvc.Name) {
+			continue
+		}
+
+		err = r.clearClaimRole(ctx, logger, pvc)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setClaimRole sets the role label and annotations on the claim. The
+// transition time is only updated when the role changes.
+func (r *VolumeReplicationReconciler) setClaimRole(
+	ctx context.Context,
+	logger logr.Logger,
+	pvc *corev1.PersistentVolumeClaim,
+	vrName, role string,
+) error {
+	transitionTime := pvc.Annotations[roleTransitionTimeAnnotation]
+	if pvc.Labels[roleLabel] != role || transitionTime == "" {
+		transitionTime = getCurrentTime().Format(time.RFC3339)
+	}
+
+	if pvc.Labels[roleLabel] == role &&
+		pvc.Annotations[volumeReplicationAnnotation] == vrName &&
+		pvc.Annotations[roleTransitionTimeAnnotation] == transitionTime {
+		return nil
+	}
+
+	logger.Info("setting role on PersistentVolumeClaim", "PVCName", pvc.Name, "Role", role)
+
+	patch := client.MergeFrom(pvc.DeepCopy())
+
+	if pvc.Labels == nil {
+		pvc.Labels = map[string]string{}
+	}
+
+	if pvc.Annotations == nil {
+		pvc.Annotations = map[string]string{}
+	}
+
+	pvc.Labels[roleLabel] = role
+	pvc.Annotations[volumeReplicationAnnotation] = vrName
+	pvc.Annotations[roleTransitionTimeAnnotation] = transitionTime
+
+	err := r.Patch(ctx, pvc, patch)
+	if err != nil {
+		return fmt.Errorf("failed to set role on PersistentVolumeClaim resource (%s/%s), %w", pvc.Namespace, pvc.Name, err)
+	}
+
+	return nil
+}
+
+// clearClaimRole removes the role label and annotations from the claim.
+func (r *VolumeReplicationReconciler) clearClaimRole(ctx context.Context, logger logr.Logger, pvc *corev1.PersistentVolumeClaim) error {
+	logger.Info("removing role from PersistentVolumeClaim", "PVCName", pvc.Name)
+
+	patch := client.MergeFrom(pvc.DeepCopy())
+
+	delete(pvc.Labels, roleLabel)
+	delete(pvc.Annotations, volumeReplicationAnnotation)
+	delete(pvc.Annotations, roleTransitionTimeAnnotation)
+
+	err := r.Patch(ctx, pvc, patch)
+	if err != nil {
+		return fmt.Errorf("failed to remove role from PersistentVolumeClaim resource (%s/%s), %w", pvc.Namespace, pvc.Name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+	"github.com/csi-addons/volume-replication-operator/pkg/client/fake"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func TestGetRoleLabelValue(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "primary", getRoleLabelValue(replicationv1alpha1.PrimaryState))
+	require.Equal(t, "secondary", getRoleLabelValue(replicationv1alpha1.SecondaryState))
+	require.Equal(t, "unknown", getRoleLabelValue(replicationv1alpha1.UnknownState))
+	require.Equal(t, "unknown", getRoleLabelValue(""))
+}
+
+func TestReconcileSetsClaimRole(t *testing.T) {
+	t.Parallel()
+
+	volumeReplication := mockVolumeReplicationObj.DeepCopy()
+	volumeReplication.Spec.DataSource.Kind = pvcDataSource
+	volumeReplication.Spec.ReplicationState = replicationv1alpha1.Primary
+
+	reconciler := createFakeVolumeReplicationReconciler(t,
+		volumeReplication,
+		mockVolumeReplicationClassObj.DeepCopy(),
+		mockPersistentVolumeClaim.DeepCopy(),
+		mockPersistentVolume.DeepCopy(),
+	)
+	reconciler.Replication = fake.NewStatefulReplicationClient(1)
+
+	key := types.NamespacedName{Name: volumeReplication.Name, Namespace: volumeReplication.Namespace}
+
+	_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	require.NoError(t, reconciler.Get(context.TODO(), types.NamespacedName{Name: mockPVCName, Namespace: mockNamespace}, pvc))
+	require.Equal(t, "primary", pvc.Labels[roleLabel])
+	require.Equal(t, volumeReplication.Name, pvc.Annotations[volumeReplicationAnnotation])
+	require.NotEmpty(t, pvc.Annotations[roleTransitionTimeAnnotation])
+}
+
+func TestSyncClaimRoles(t *testing.T) {
+	t.Parallel()
+
+	volumeReplication := mockVolumeReplicationObj.DeepCopy()
+	volumeReplication.Spec.DataSource.Kind = volumeGroupDataSource
+	volumeReplication.Status.State = replicationv1alpha1.SecondaryState
+	volumeReplication.Status.Members = []replicationv1alpha1.VolumeReplicationMember{
+		{PVCName: "member-0", Bound: true},
+	}
+
+	claim := func(name string, labels, annotations map[string]string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   mockNamespace,
+				Labels:      labels,
+				Annotations: annotations,
+			},
+		}
+	}
+
+	reconciler := createFakeVolumeReplicationReconciler(t,
+		volumeReplication,
+		claim("member-0", map[string]string{roleLabel: "primary"}, map[string]string{
+			volumeReplicationAnnotation:  volumeReplication.Name,
+			roleTransitionTimeAnnotation: "2025-01-01T00:00:00Z",
+		}),
+		// removed from the VolumeGroup
+		claim("member-1", map[string]string{roleLabel: "primary", "app": "db"}, map[string]string{
+			volumeReplicationAnnotation:  volumeReplication.Name,
+			roleTransitionTimeAnnotation: "2025-01-01T00:00:00Z",
+		}),
+		// replicated by another VolumeReplication
+		claim("other", map[string]string{roleLabel: "primary"}, map[string]string{
+			volumeReplicationAnnotation: "other",
+		}),
+	)
+
+	getClaim := func(name string) *corev1.PersistentVolumeClaim {
+		pvc := &corev1.PersistentVolumeClaim{}
+		require.NoError(t, reconciler.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: mockNamespace}, pvc))
+
+		return pvc
+	}
+
+	require.NoError(t, reconciler.syncClaimRoles(context.TODO(), logf.Log, volumeReplication))
+
+	member := getClaim("member-0")
+	require.Equal(t, "secondary", member.Labels[roleLabel])
+	require.NotEqual(t, "2025-01-01T00:00:00Z", member.Annotations[roleTransitionTimeAnnotation])
+
+	removed := getClaim("member-1")
+	require.NotContains(t, removed.Labels, roleLabel)
+	require.Equal(t, "db", removed.Labels["app"])
+	require.NotContains(t, removed.Annotations, volumeReplicationAnnotation)
+	require.NotContains(t, removed.Annotations, roleTransitionTimeAnnotation)
+
+	require.Equal(t, "primary", getClaim("other").Labels[roleLabel])
+
+	// the transition time is kept while the role is unchanged
+	transitionTime := member.Annotations[roleTransitionTimeAnnotation]
+	require.NoError(t, reconciler.syncClaimRoles(context.TODO(), logf.Log, volumeReplication))
+	require.Equal(t, transitionTime, getClaim("member-0").Annotations[roleTransitionTimeAnnotation])
+
+	// all claims are cleared once the VolumeReplication is deleted
+	now := metav1.Now()
+	volumeReplication.DeletionTimestamp = &now
+	require.NoError(t, reconciler.syncClaimRoles(context.TODO(), logf.Log, volumeReplication))
+	require.NotContains(t, getClaim("member-0").Labels, roleLabel)
+	require.Equal(t, "primary", getClaim("other").Labels[roleLabel])
+}
+
+func TestUpdateReplicationStatusSyncsChangedRole(t *testing.T) {
+	t.Parallel()
+
+	volumeReplication := mockVolumeReplicationObj.DeepCopy()
+	volumeReplication.Spec.DataSource.Kind = pvcDataSource
+	volumeReplication.Status.State = replicationv1alpha1.PrimaryState
+
+	reconciler := createFakeVolumeReplicationReconciler(t, volumeReplication, mockPersistentVolumeClaim.DeepCopy())
+
+	fakeClient, ok := reconciler.Client.(client.WithWatch)
+	require.True(t, ok)
+
+	lists := 0
+	reconciler.Client = interceptor.NewClient(fakeClient, interceptor.Funcs{
+		List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			if _, ok := list.(*corev1.PersistentVolumeClaimList); ok {
+				lists++
+			}
+
+			return c.List(ctx, list, opts...)
+		},
+	})
+
+	ctx := context.TODO()
+	key := types.NamespacedName{Name: volumeReplication.Name, Namespace: volumeReplication.Namespace}
+
+	instance := &replicationv1alpha1.VolumeReplication{}
+	require.NoError(t, reconciler.Get(ctx, key, instance))
+
+	// the claims are left alone while the role is unchanged
+	require.NoError(t, reconciler.updateReplicationStatus(ctx, instance, logf.Log, replicationv1alpha1.PrimaryState, "volume is marked primary"))
+	require.Zero(t, lists)
+
+	require.NoError(t, reconciler.updateReplicationStatus(ctx, instance, logf.Log, replicationv1alpha1.SecondaryState, "volume is marked secondary"))
+	require.Equal(t, 1, lists)
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	require.NoError(t, reconciler.Get(ctx, types.NamespacedName{Name: mockPVCName, Namespace: mockNamespace}, pvc))
+	require.Equal(t, "secondary", pvc.Labels[roleLabel])
+}
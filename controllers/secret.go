@@ -18,15 +18,37 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+var (
+	// secretTemplateVariable matches the ${...} variables of the secret
+	// parameters.
+	secretTemplateVariable = regexp.MustCompile(`\$\{([^}]*)\}`)
+	// secretTemplateAnnotation matches the variables of the annotations of
+	// the replication source.
+	secretTemplateAnnotation = regexp.MustCompile(`^pvc\.annotations\['([^']*)'\]$`)
+	// secretNamespaceVariables are the only variables of the secret
+	// namespace, so that the names and annotations chosen by a tenant cannot
+	// point to the secrets of another namespace.
+	secretNamespaceVariables = []string{"vr.namespace", "pvc.namespace"}
 )
 
-// getSecret retrieves the secrets based on name and namespace input.
-func (r *VolumeReplicationReconciler) getSecret(ctx context.Context, logger logr.Logger, name, namespace string) (map[string]string, error) {
+// getSecret retrieves the secrets based on name and namespace input, together
+// with the resourceVersion of the secret.
+func (r *VolumeReplicationReconciler) getSecret(ctx context.Context, logger logr.Logger, name, namespace string) (map[string]string, string, error) {
 	namespacedName := types.NamespacedName{Name: name, Namespace: namespace}
 	secret := &corev1.Secret{}
 
@@ -35,15 +57,15 @@ func (r *VolumeReplicationReconciler) getSecret(ctx context.Context, logger logr
 		if apierrors.IsNotFound(err) {
 			logger.Error(err, "secret not found", "Secret Name", name, "Secret Namespace", namespace)
 
-			return nil, err
+			return nil, "", err
 		}
 
 		logger.Error(err, "error getting secret", "Secret Name", name, "Secret Namespace", namespace)
 
-		return nil, err
+		return nil, "", err
 	}
 
-	return convertMap(secret.Data), nil
+	return convertMap(secret.Data), secret.ResourceVersion, nil
 }
 
 // convertMap converts map[string][]byte to map[string]string.
@@ -56,3 +78,109 @@ func convertMap(oldMap map[string][]byte) map[string]string {
 
 	return newMap
 }
+
+// getSecretReference returns the name and namespace of the secret of the
+// class, with their template variables resolved against the VolumeReplication
+// and its source, the claim or the VolumeGroup. Both are empty if the class
+// sets no secret.
+func getSecretReference(
+	parameters map[string]string,
+	instance *replicationv1alpha1.VolumeReplication,
+	source metav1.Object,
+) (string, string, error) {
+	name := parameters[prefixedReplicationSecretNameKey]
+	namespace := parameters[prefixedReplicationSecretNamespaceKey]
+
+	if name == "" || namespace == "" {
+		return "", "", nil
+	}
+
+	name, err := resolveSecretTemplate(name, instance, source)
+	if err != nil {
+		return "", "", err
+	}
+
+	err = validateSecretNamespaceTemplate(namespace)
+	if err != nil {
+		return "", "", err
+	}
+
+	namespace, err = resolveSecretTemplate(namespace, instance, source)
+	if err != nil {
+		return "", "", err
+	}
+
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return "", "", fmt.Errorf("invalid secret name %q: %s", name, strings.Join(errs, ", "))
+	}
+
+	if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+		return "", "", fmt.Errorf("invalid secret namespace %q: %s", namespace, strings.Join(errs, ", "))
+	}
+
+	return name, namespace, nil
+}
+
+// validateSecretNamespaceTemplate returns an error if the secret namespace
+// uses other variables than the namespace of the VolumeReplication or of its
+// source.
+func validateSecretNamespaceTemplate(template string) error {
+	for _, match := range secretTemplateVariable.FindAllStringSubmatch(template, -1) {
+		variable := strings.TrimSpace(match[1])
+		if !slices.Contains(secretNamespaceVariables, variable) {
+			return fmt.Errorf("variable %q is not allowed in secret namespace %q, only %s are",
+				variable, template, strings.Join(secretNamespaceVariables, " and "))
+		}
+	}
+
+	return nil
+}
+
+// resolveSecretTemplate replaces the ${vr.name}, ${vr.namespace},
+// ${pvc.name}, ${pvc.namespace} and ${pvc.annotations['<key>']} variables of
+// the template. The pvc variables refer to the VolumeGroup for group sources.
+func resolveSecretTemplate(template string, instance *replicationv1alpha1.VolumeReplication, source metav1.Object) (string, error) {
+	var errs []string
+
+	resolved := secretTemplateVariable.ReplaceAllStringFunc(template, func(match string) string {
+		variable := strings.TrimSpace(secretTemplateVariable.FindStringSubmatch(match)[1])
+
+		value, err := getSecretTemplateValue(variable, instance, source)
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+
+		return value
+	})
+
+	if len(errs) > 0 {
+		return "", fmt.Errorf("failed to resolve secret parameter %q: %s", template, strings.Join(errs, ", "))
+	}
+
+	return resolved, nil
+}
+
+// getSecretTemplateValue returns the value of a template variable.
+func getSecretTemplateValue(variable string, instance *replicationv1alpha1.VolumeReplication, source metav1.Object) (string, error) {
+	switch variable {
+	case "vr.name":
+		return instance.Name, nil
+	case "vr.namespace":
+		return instance.Namespace, nil
+	case "pvc.name":
+		return source.GetName(), nil
+	case "pvc.namespace":
+		return source.GetNamespace(), nil
+	}
+
+	if match := secretTemplateAnnotation.FindStringSubmatch(variable); match != nil {
+		value, ok := source.GetAnnotations()[match[1]]
+		if !ok {
+			return "", fmt.Errorf("annotation %q of %q not found", match[1], source.GetName())
+		}
+
+		return value, nil
+	}
+
+	return "", fmt.Errorf("unknown variable %q", variable)
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	volumegroupv1 "github.com/IBM/csi-volume-group-operator/api/v1"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetSecretReference(t *testing.T) {
+	t.Parallel()
+
+	pvc := mockPersistentVolumeClaim.DeepCopy()
+	pvc.Annotations = map[string]string{"example.com/tenant": "tenant-a"}
+
+	vg := &volumegroupv1.VolumeGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "group",
+			Namespace:   mockNamespace,
+			Annotations: map[string]string{"example.com/tenant": "tenant-b"},
+		},
+	}
+
+	secretParameters := func(name, namespace string) map[string]string {
+		return map[string]string{
+			prefixedReplicationSecretNameKey:      name,
+			prefixedReplicationSecretNamespaceKey: namespace,
+		}
+	}
+
+	tests := []struct {
+		name          string
+		parameters    map[string]string
+		source        metav1.Object
+		wantName      string
+		wantNamespace string
+		wantErr       string
+	}{
+		{
+			name:       "no secret",
+			parameters: map[string]string{},
+			source:     pvc,
+		},
+		{
+			name:          "fixed secret",
+			parameters:    secretParameters("secret", "storage"),
+			source:        pvc,
+			wantName:      "secret",
+			wantNamespace: "storage",
+		},
+		{
+			name:          "claim variables",
+			parameters:    secretParameters("${pvc.name}-${vr.name}", "${pvc.namespace}"),
+			source:        pvc,
+			wantName:      "test-pvc-volume-replication",
+			wantNamespace: mockNamespace,
+		},
+		{
+			name:          "claim annotation",
+			parameters:    secretParameters("${pvc.annotations['example.com/tenant']}-secret", "${vr.namespace}"),
+			source:        pvc,
+			wantName:      "tenant-a-secret",
+			wantNamespace: mockNamespace,
+		},
+		{
+			name:          "volume group source",
+			parameters:    secretParameters("${pvc.annotations['example.com/tenant']}-${pvc.name}", "${pvc.namespace}"),
+			source:        vg,
+			wantName:      "tenant-b-group",
+			wantNamespace: mockNamespace,
+		},
+		{
+			name:       "missing annotation",
+			parameters: secretParameters("${pvc.annotations['example.com/missing']}", "storage"),
+			source:     pvc,
+			wantErr:    `annotation "example.com/missing" of "test-pvc" not found`,
+		},
+		{
+			name:       "unknown variable",
+			parameters: secretParameters("${pv.name}", "storage"),
+			source:     pvc,
+			wantErr:    `unknown variable "pv.name"`,
+		},
+		{
+			name:       "annotation in namespace",
+			parameters: secretParameters("secret", "${pvc.annotations['example.com/tenant']}"),
+			source:     pvc,
+			wantErr:    `variable "pvc.annotations['example.com/tenant']" is not allowed in secret namespace`,
+		},
+		{
+			name:       "name in namespace",
+			parameters: secretParameters("secret", "${pvc.name}"),
+			source:     pvc,
+			wantErr:    `variable "pvc.name" is not allowed in secret namespace`,
+		},
+		{
+			name:       "invalid resolved name",
+			parameters: secretParameters("${pvc.annotations['example.com/tenant']}_secret", "storage"),
+			source:     pvc,
+			wantErr:    `invalid secret name "tenant-a_secret"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			name, namespace, err := getSecretReference(tt.parameters, mockVolumeReplicationObj, tt.source)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.wantName, name)
+			require.Equal(t, tt.wantNamespace, namespace)
+		})
+	}
+}
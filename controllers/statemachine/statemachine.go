@@ -0,0 +1,180 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package statemachine defines the transitions of a replicated volume from
+// its current state to the desired replication state, and the replication
+// operations each transition consists of.
+package statemachine
+
+import (
+	"errors"
+	"fmt"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+)
+
+// Step is a replication operation issued to the driver.
+type Step string
+
+const (
+	// Promote promotes the volume to primary.
+	Promote Step = "Promote"
+	// Demote demotes the volume to secondary.
+	Demote Step = "Demote"
+	// Resync checks whether the volume is in sync, forcing a resync only if
+	// auto resync is enabled.
+	Resync Step = "Resync"
+	// ForceResync forces a resync of the volume.
+	ForceResync Step = "ForceResync"
+)
+
+var (
+	// ErrUnsupportedState is returned for an unknown desired replication
+	// state.
+	ErrUnsupportedState = errors.New("unsupported volume state")
+	// ErrUnsafeTransition is returned for a transition that would discard
+	// the data of the volume while it is in use.
+	ErrUnsafeTransition = errors.New("unsafe transition")
+	// ErrDemotionBlocked is returned for a demotion of a volume in use.
+	ErrDemotionBlocked = errors.New("demotion blocked")
+)
+
+// Transition moves a volume from its current state to a desired replication
+// state.
+type Transition struct {
+	// From is the current state of the volume.
+	From replicationv1alpha1.State
+	// To is the desired replication state.
+	To replicationv1alpha1.ReplicationState
+	// Steps are the replication operations, run in order.
+	Steps []Step
+	// Target is the state of the volume once all steps succeeded.
+	Target replicationv1alpha1.State
+	// Settle requeues the volume once the steps succeeded, as some storage
+	// providers take time to determine whether a volume that was just
+	// demoted needs correction, e.g. a split brain.
+	Settle bool
+	// UnsafeWhileInUse rejects the transition while the volume is in use, as
+	// it would discard data written by its consumers.
+	UnsafeWhileInUse bool
+	// Demotion marks the demotion of a volume that might be primary, it is
+	// blocked while the volume is in use as its consumers would get I/O
+	// errors.
+	Demotion bool
+}
+
+// transitions is the transition table, indexed by the current state and the
+// desired replication state.
+var transitions = map[replicationv1alpha1.State]map[replicationv1alpha1.ReplicationState]Transition{
+	replicationv1alpha1.UnknownState: {
+		replicationv1alpha1.Primary: {
+			Steps:  []Step{Promote},
+			Target: replicationv1alpha1.PrimaryState,
+		},
+		replicationv1alpha1.Secondary: {
+			Steps:    []Step{Demote},
+			Target:   replicationv1alpha1.SecondaryState,
+			Settle:   true,
+			Demotion: true,
+		},
+		// the volume might be primary, so a resync is as unsafe as for a
+		// primary volume.
+		replicationv1alpha1.Resync: {
+			Steps:            []Step{ForceResync},
+			Target:           replicationv1alpha1.SecondaryState,
+			UnsafeWhileInUse: true,
+		},
+	},
+	replicationv1alpha1.PrimaryState: {
+		replicationv1alpha1.Primary: {
+			Steps:  []Step{Promote},
+			Target: replicationv1alpha1.PrimaryState,
+		},
+		replicationv1alpha1.Secondary: {
+			Steps:    []Step{Demote},
+			Target:   replicationv1alpha1.SecondaryState,
+			Settle:   true,
+			Demotion: true,
+		},
+		replicationv1alpha1.Resync: {
+			Steps:            []Step{ForceResync},
+			Target:           replicationv1alpha1.SecondaryState,
+			UnsafeWhileInUse: true,
+		},
+	},
+	replicationv1alpha1.SecondaryState: {
+		replicationv1alpha1.Primary: {
+			Steps:  []Step{Promote},
+			Target: replicationv1alpha1.PrimaryState,
+		},
+		replicationv1alpha1.Secondary: {
+			Steps:  []Step{Demote, Resync},
+			Target: replicationv1alpha1.SecondaryState,
+		},
+		replicationv1alpha1.Resync: {
+			Steps:  []Step{ForceResync},
+			Target: replicationv1alpha1.SecondaryState,
+		},
+	},
+}
+
+// Lookup returns the transition from the current state to the desired
+// replication state. An empty current state is treated as unknown.
+func Lookup(current replicationv1alpha1.State, desired replicationv1alpha1.ReplicationState) (Transition, error) {
+	if current == "" {
+		current = replicationv1alpha1.UnknownState
+	}
+
+	from, ok := transitions[current]
+	if !ok {
+		// a state that is not in the table is as good as unknown
+		from = transitions[replicationv1alpha1.UnknownState]
+	}
+
+	t, ok := from[desired]
+	if !ok {
+		return Transition{}, fmt.Errorf("%w %q", ErrUnsupportedState, desired)
+	}
+
+	t.From = current
+	t.To = desired
+
+	return t, nil
+}
+
+// InUseChecked returns true if the transition depends on whether the volume
+// is in use.
+func (t Transition) InUseChecked() bool {
+	return t.UnsafeWhileInUse || t.Demotion
+}
+
+// Validate returns an error wrapping ErrUnsafeTransition or
+// ErrDemotionBlocked if the transition is not allowed for a volume in use by
+// the given consumers.
+func (t Transition) Validate(consumers []string) error {
+	if len(consumers) == 0 {
+		return nil
+	}
+
+	switch {
+	case t.UnsafeWhileInUse:
+		return fmt.Errorf("%w from %s to %s: volume is in use by %v", ErrUnsafeTransition, t.From, t.To, consumers)
+	case t.Demotion:
+		return fmt.Errorf("%w: volume is in use by %v", ErrDemotionBlocked, consumers)
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statemachine
+
+import (
+	"testing"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		current  replicationv1alpha1.State
+		desired  replicationv1alpha1.ReplicationState
+		steps    []Step
+		target   replicationv1alpha1.State
+		settle   bool
+		unsafe   bool
+		demotion bool
+		expected error
+	}{
+		{
+			current: "",
+			desired: replicationv1alpha1.Primary,
+			steps:   []Step{Promote},
+			target:  replicationv1alpha1.PrimaryState,
+		},
+		{
+			current: replicationv1alpha1.SecondaryState,
+			desired: replicationv1alpha1.Primary,
+			steps:   []Step{Promote},
+			target:  replicationv1alpha1.PrimaryState,
+		},
+		{
+			current:  replicationv1alpha1.PrimaryState,
+			desired:  replicationv1alpha1.Secondary,
+			steps:    []Step{Demote},
+			target:   replicationv1alpha1.SecondaryState,
+			settle:   true,
+			demotion: true,
+		},
+		{
+			current: replicationv1alpha1.SecondaryState,
+			desired: replicationv1alpha1.Secondary,
+			steps:   []Step{Demote, Resync},
+			target:  replicationv1alpha1.SecondaryState,
+		},
+		{
+			current: replicationv1alpha1.PrimaryState,
+			desired: replicationv1alpha1.Resync,
+			steps:   []Step{ForceResync},
+			target:  replicationv1alpha1.SecondaryState,
+			unsafe:  true,
+		},
+		{
+			current: replicationv1alpha1.UnknownState,
+			desired: replicationv1alpha1.Resync,
+			steps:   []Step{ForceResync},
+			target:  replicationv1alpha1.SecondaryState,
+			unsafe:  true,
+		},
+		{
+			current: replicationv1alpha1.SecondaryState,
+			desired: replicationv1alpha1.Resync,
+			steps:   []Step{ForceResync},
+			target:  replicationv1alpha1.SecondaryState,
+		},
+		{
+			current:  replicationv1alpha1.PrimaryState,
+			desired:  "invalid",
+			expected: ErrUnsupportedState,
+		},
+	}
+
+	for _, tc := range testcases {
+		transition, err := Lookup(tc.current, tc.desired)
+		if tc.expected != nil {
+			require.ErrorIs(t, err, tc.expected)
+
+			continue
+		}
+
+		require.NoError(t, err)
+		require.Equal(t, tc.steps, transition.Steps, "%s to %s", tc.current, tc.desired)
+		require.Equal(t, tc.target, transition.Target, "%s to %s", tc.current, tc.desired)
+		require.Equal(t, tc.settle, transition.Settle, "%s to %s", tc.current, tc.desired)
+		require.Equal(t, tc.unsafe, transition.UnsafeWhileInUse, "%s to %s", tc.current, tc.desired)
+		require.Equal(t, tc.demotion, transition.Demotion, "%s to %s", tc.current, tc.desired)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	transition, err := Lookup(replicationv1alpha1.PrimaryState, replicationv1alpha1.Resync)
+	require.NoError(t, err)
+
+	require.NoError(t, transition.Validate(nil))
+	require.ErrorIs(t, transition.Validate([]string{"pod/app-0"}), ErrUnsafeTransition)
+
+	transition, err = Lookup(replicationv1alpha1.PrimaryState, replicationv1alpha1.Secondary)
+	require.NoError(t, err)
+	require.ErrorIs(t, transition.Validate([]string{"pod/app-0"}), ErrDemotionBlocked)
+
+	transition, err = Lookup(replicationv1alpha1.SecondaryState, replicationv1alpha1.Secondary)
+	require.NoError(t, err)
+	require.False(t, transition.InUseChecked())
+	require.NoError(t, transition.Validate([]string{"pod/app-0"}))
+}
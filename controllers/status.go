@@ -17,7 +17,9 @@ limitations under the License.
 package controllers
 
 import (
-	"time"
+	"fmt"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -26,6 +28,15 @@ const (
 	ConditionCompleted = "Completed"
 	ConditionDegraded  = "Degraded"
 	ConditionResyncing = "Resyncing"
+	ConditionPending   = "Pending"
+
+	ConditionMembershipChanged = "MembershipChanged"
+	ConditionPaused            = "Paused"
+	ConditionDemotionBlocked   = "DemotionBlocked"
+	ConditionHookFailed        = "HookFailed"
+
+	ConditionWaitingForDependency = "WaitingForDependency"
+	ConditionClassNotPermitted    = "ClassNotPermitted"
 )
 
 const (
@@ -40,6 +51,32 @@ const (
 	ResyncTriggered = "ResyncTriggered"
 	FailedToResync  = "FailedToResync"
 	NotResyncing    = "NotResyncing"
+
+	VolumeGroupNotReady = "VolumeGroupNotReady"
+	MembershipChanged   = "MembershipChanged"
+	MembershipUnchanged = "MembershipUnchanged"
+
+	VolumeGroupUnavailable = "VolumeGroupUnavailable"
+
+	PausedByVolumeReplication      = "PausedByVolumeReplication"
+	PausedByVolumeReplicationClass = "PausedByVolumeReplicationClass"
+	DeletionPaused                 = "DeletionPaused"
+
+	UnsafeTransition = "UnsafeTransition"
+	VolumeInUse      = "VolumeInUse"
+
+	PreDemoteHookFailed   = "PreDemoteHookFailed"
+	PostPromoteHookFailed = "PostPromoteHookFailed"
+
+	DependencyNotPrimary  = "DependencyNotPrimary"
+	DependentNotSecondary = "DependentNotSecondary"
+	DependencyCycle       = "DependencyCycle"
+
+	NamespaceNotAllowed = "NamespaceNotAllowed"
+	QuotaExceeded       = "QuotaExceeded"
+
+	MembersCompleted = "MembersCompleted"
+	MembersPending   = "MembersPending"
 )
 
 // sets conditions when volume was promoted successfully.
@@ -190,6 +227,192 @@ func setFailedResyncCondition(conditions *[]metav1.Condition, observedGeneration
 	})
 }
 
+// sets conditions when the data source is not yet ready to be replicated.
+func setPendingCondition(conditions *[]metav1.Condition, observedGeneration int64, message string) {
+	setStatusCondition(conditions, &metav1.Condition{
+		Type:               ConditionPending,
+		Reason:             VolumeGroupNotReady,
+		Message:            message,
+		ObservedGeneration: observedGeneration,
+		Status:             metav1.ConditionTrue,
+	})
+}
+
+// removes the pending condition once the data source is ready.
+func removePendingCondition(conditions *[]metav1.Condition) {
+	removeStatusCondition(conditions, ConditionPending)
+}
+
+// sets conditions when the VolumeGroup data source cannot be used as the
+// VolumeGroup CRDs are not installed.
+func setVolumeGroupUnavailableCondition(conditions *[]metav1.Condition, observedGeneration int64, message string) {
+	setStatusCondition(conditions, &metav1.Condition{
+		Type:               ConditionCompleted,
+		Reason:             VolumeGroupUnavailable,
+		Message:            message,
+		ObservedGeneration: observedGeneration,
+		Status:             metav1.ConditionFalse,
+	})
+	setStatusCondition(conditions, &metav1.Condition{
+		Type:               ConditionDegraded,
+		Reason:             Error,
+		ObservedGeneration: observedGeneration,
+		Status:             metav1.ConditionTrue,
+	})
+}
+
+// removes the conditions set while the VolumeGroup CRDs were not installed,
+// once they are.
+func removeVolumeGroupUnavailableCondition(conditions *[]metav1.Condition) {
+	completed := findCondition(*conditions, ConditionCompleted)
+	if completed == nil || completed.Reason != VolumeGroupUnavailable {
+		return
+	}
+
+	removeStatusCondition(conditions, ConditionCompleted)
+	removeStatusCondition(conditions, ConditionDegraded)
+}
+
+// sets conditions when the members of the VolumeGroup changed.
+func setMembershipChangedCondition(conditions *[]metav1.Condition, observedGeneration int64, message string) {
+	setStatusCondition(conditions, &metav1.Condition{
+		Type:               ConditionMembershipChanged,
+		Reason:             MembershipChanged,
+		Message:            message,
+		ObservedGeneration: observedGeneration,
+		Status:             metav1.ConditionTrue,
+	})
+}
+
+// sets conditions when the members of the VolumeGroup are first recorded or
+// did not change since the last reconcile.
+func setMembershipUnchangedCondition(conditions *[]metav1.Condition, observedGeneration int64) {
+	setStatusCondition(conditions, &metav1.Condition{
+		Type:               ConditionMembershipChanged,
+		Reason:             MembershipUnchanged,
+		ObservedGeneration: observedGeneration,
+		Status:             metav1.ConditionFalse,
+	})
+}
+
+// sets conditions when the reconciliation of the volume is paused.
+func setPausedCondition(conditions *[]metav1.Condition, observedGeneration int64, reason, message string) {
+	setStatusCondition(conditions, &metav1.Condition{
+		Type:               ConditionPaused,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: observedGeneration,
+		Status:             metav1.ConditionTrue,
+	})
+}
+
+// removes the paused condition once the reconciliation is resumed.
+func removePausedCondition(conditions *[]metav1.Condition) {
+	removeStatusCondition(conditions, ConditionPaused)
+}
+
+// sets the class not permitted condition while the VolumeReplication may not
+// use its class.
+func setClassNotPermittedCondition(conditions *[]metav1.Condition, observedGeneration int64, reason, message string) {
+	setStatusCondition(conditions, &metav1.Condition{
+		Type:               ConditionClassNotPermitted,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: observedGeneration,
+		Status:             metav1.ConditionTrue,
+	})
+}
+
+// removes the class not permitted condition once the class may be used.
+func removeClassNotPermittedCondition(conditions *[]metav1.Condition) {
+	removeStatusCondition(conditions, ConditionClassNotPermitted)
+}
+
+// sets conditions when the transition to the desired state is rejected as
+// unsafe.
+func setUnsafeTransitionCondition(conditions *[]metav1.Condition, observedGeneration int64, message string) {
+	setStatusCondition(conditions, &metav1.Condition{
+		Type:               ConditionCompleted,
+		Reason:             UnsafeTransition,
+		Message:            message,
+		ObservedGeneration: observedGeneration,
+		Status:             metav1.ConditionFalse,
+	})
+}
+
+// sets conditions when the demotion of the volume is blocked as it is in use.
+func setDemotionBlockedCondition(conditions *[]metav1.Condition, observedGeneration int64, message string) {
+	setStatusCondition(conditions, &metav1.Condition{
+		Type:               ConditionDemotionBlocked,
+		Reason:             VolumeInUse,
+		Message:            message,
+		ObservedGeneration: observedGeneration,
+		Status:             metav1.ConditionTrue,
+	})
+}
+
+// removes the demotion blocked condition once the volume is no longer in use.
+func removeDemotionBlockedCondition(conditions *[]metav1.Condition) {
+	removeStatusCondition(conditions, ConditionDemotionBlocked)
+}
+
+// sets conditions when a transition hook failed.
+func setHookFailedCondition(conditions *[]metav1.Condition, observedGeneration int64,
+	hookType replicationv1alpha1.HookType, message string,
+) {
+	reason := PostPromoteHookFailed
+	if hookType == replicationv1alpha1.PreDemoteHook {
+		reason = PreDemoteHookFailed
+	}
+
+	setStatusCondition(conditions, &metav1.Condition{
+		Type:               ConditionHookFailed,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: observedGeneration,
+		Status:             metav1.ConditionTrue,
+	})
+}
+
+// removes the hook failed condition once the hooks completed.
+func removeHookFailedCondition(conditions *[]metav1.Condition) {
+	removeStatusCondition(conditions, ConditionHookFailed)
+}
+
+// sets conditions when the transition waits for other VolumeReplications.
+func setWaitingForDependencyCondition(conditions *[]metav1.Condition, observedGeneration int64, reason, message string) {
+	setStatusCondition(conditions, &metav1.Condition{
+		Type:               ConditionWaitingForDependency,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: observedGeneration,
+		Status:             metav1.ConditionTrue,
+	})
+}
+
+// removes the waiting for dependency condition once the transition can run.
+func removeWaitingForDependencyCondition(conditions *[]metav1.Condition) {
+	removeStatusCondition(conditions, ConditionWaitingForDependency)
+}
+
+// sets conditions of a policy from the transitions of its members.
+func setPolicyCompletedCondition(conditions *[]metav1.Condition, observedGeneration int64, completed, total int) {
+	condition := &metav1.Condition{
+		Type:               ConditionCompleted,
+		Reason:             MembersCompleted,
+		Message:            fmt.Sprintf("%d of %d members completed", completed, total),
+		ObservedGeneration: observedGeneration,
+		Status:             metav1.ConditionTrue,
+	}
+
+	if completed != total {
+		condition.Reason = MembersPending
+		condition.Status = metav1.ConditionFalse
+	}
+
+	setStatusCondition(conditions, condition)
+}
+
 func setStatusCondition(existingConditions *[]metav1.Condition, newCondition *metav1.Condition) {
 	if existingConditions == nil {
 		existingConditions = &[]metav1.Condition{}
@@ -197,7 +420,7 @@ func setStatusCondition(existingConditions *[]metav1.Condition, newCondition *me
 
 	existingCondition := findCondition(*existingConditions, newCondition.Type)
 	if existingCondition == nil {
-		newCondition.LastTransitionTime = metav1.NewTime(time.Now())
+		newCondition.LastTransitionTime = *getCurrentTime()
 		*existingConditions = append(*existingConditions, *newCondition)
 
 		return
@@ -205,13 +428,30 @@ func setStatusCondition(existingConditions *[]metav1.Condition, newCondition *me
 
 	if existingCondition.Status != newCondition.Status {
 		existingCondition.Status = newCondition.Status
-		existingCondition.LastTransitionTime = metav1.NewTime(time.Now())
+		existingCondition.LastTransitionTime = *getCurrentTime()
 	}
 
 	existingCondition.Reason = newCondition.Reason
+	existingCondition.Message = newCondition.Message
 	existingCondition.ObservedGeneration = newCondition.ObservedGeneration
 }
 
+func removeStatusCondition(existingConditions *[]metav1.Condition, conditionType string) {
+	if existingConditions == nil {
+		return
+	}
+
+	newConditions := make([]metav1.Condition, 0, len(*existingConditions))
+
+	for _, condition := range *existingConditions {
+		if condition.Type != conditionType {
+			newConditions = append(newConditions, condition)
+		}
+	}
+
+	*existingConditions = newConditions
+}
+
 func findCondition(existingConditions []metav1.Condition, conditionType string) *metav1.Condition {
 	for i := range existingConditions {
 		if existingConditions[i].Type == conditionType {
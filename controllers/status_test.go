@@ -0,0 +1,109 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+	"github.com/csi-addons/volume-replication-operator/pkg/client/fake"
+
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestUpdateReplicationStatus(t *testing.T) {
+	t.Parallel()
+
+	volumeReplication := &replicationv1alpha1.VolumeReplication{}
+	mockVolumeReplicationObj.DeepCopyInto(volumeReplication)
+
+	reconciler := createFakeVolumeReplicationReconciler(t, volumeReplication)
+	ctx := context.TODO()
+	key := client.ObjectKeyFromObject(volumeReplication)
+
+	instance := &replicationv1alpha1.VolumeReplication{}
+	require.NoError(t, reconciler.Get(ctx, key, instance))
+
+	setPromotedCondition(&instance.Status.Conditions, instance.Generation)
+	err := reconciler.updateReplicationStatus(ctx, instance, reconciler.Log, replicationv1alpha1.PrimaryState, "volume is marked primary")
+	require.NoError(t, err)
+
+	persisted := &replicationv1alpha1.VolumeReplication{}
+	require.NoError(t, reconciler.Get(ctx, key, persisted))
+	require.Equal(t, replicationv1alpha1.PrimaryState, persisted.Status.State)
+	require.NotNil(t, findCondition(persisted.Status.Conditions, ConditionCompleted))
+
+	// unchanged status is not written again
+	resourceVersion := persisted.ResourceVersion
+	err = reconciler.updateReplicationStatus(ctx, instance, reconciler.Log, replicationv1alpha1.PrimaryState, "volume is marked primary")
+	require.NoError(t, err)
+	require.NoError(t, reconciler.Get(ctx, key, persisted))
+	require.Equal(t, resourceVersion, persisted.ResourceVersion)
+
+	// a stale copy does not overwrite the latest status
+	stale := instance.DeepCopy()
+	stale.ResourceVersion = "1"
+	err = reconciler.updateReplicationStatus(ctx, stale, reconciler.Log, replicationv1alpha1.SecondaryState, "volume is marked secondary")
+	require.True(t, apierrors.IsConflict(err))
+	require.NoError(t, reconciler.Get(ctx, key, persisted))
+	require.Equal(t, replicationv1alpha1.PrimaryState, persisted.Status.State)
+}
+
+func TestReconcileSkipsUnchangedStatus(t *testing.T) {
+	t.Parallel()
+
+	volumeReplication := mockVolumeReplicationObj.DeepCopy()
+	volumeReplication.Spec.DataSource.Kind = pvcDataSource
+	volumeReplication.Spec.ReplicationState = replicationv1alpha1.Primary
+
+	reconciler := createFakeVolumeReplicationReconciler(t,
+		volumeReplication,
+		mockVolumeReplicationClassObj.DeepCopy(),
+		mockPersistentVolumeClaim.DeepCopy(),
+		mockPersistentVolume.DeepCopy(),
+	)
+	reconciler.Replication = fake.NewStatefulReplicationClient(1)
+
+	ctx := context.TODO()
+	key := client.ObjectKeyFromObject(volumeReplication)
+
+	_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	latest := &replicationv1alpha1.VolumeReplication{}
+	require.NoError(t, reconciler.Get(ctx, key, latest))
+	require.NotNil(t, latest.Status.LastCompletionTime)
+
+	completedAt := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	latest.Status.LastCompletionTime = &completedAt
+	require.NoError(t, reconciler.Status().Update(ctx, latest))
+
+	// a reconcile that changes nothing does not write the status
+	_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	persisted := &replicationv1alpha1.VolumeReplication{}
+	require.NoError(t, reconciler.Get(ctx, key, persisted))
+	require.Equal(t, latest.ResourceVersion, persisted.ResourceVersion)
+	require.True(t, completedAt.Equal(persisted.Status.LastCompletionTime))
+}
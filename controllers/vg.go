@@ -18,13 +18,32 @@ package controllers
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strconv"
 
 	volumegroupv1 "github.com/IBM/csi-volume-group-operator/api/v1"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+
 	"github.com/go-logr/logr"
-	"k8s.io/apimachinery/pkg/api/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
+// forceDeletionAnnotation allows deleting a VolumeReplication whose
+// VolumeGroup never becomes ready, without disabling its replication.
+const forceDeletionAnnotation = replicationParameterPrefix + "force-deletion"
+
+// errVGNotReady is returned when the VolumeGroup exists but is not yet
+// bound to a VolumeGroupContent or is not ready for use.
+var errVGNotReady = errors.New("volumeGroup is not ready")
+
 // getVGDataSource get vg content, vg object from the request.
 //
 //nolint:gocritic
@@ -35,25 +54,176 @@ func (r VolumeReplicationReconciler) getVGDataSource(ctx context.Context, logger
 
 	err := r.Get(ctx, req, vg)
 	if err != nil {
-		if errors.IsNotFound(err) {
+		if apierrors.IsNotFound(err) {
 			logger.Error(err, "VG not found", "VG Name", req.Name)
 		}
 
 		return nil, nil, err
 	}
 
+	volumeGroupContentName := getVGContentName(vg)
+	if volumeGroupContentName == "" {
+		return vg, nil, fmt.Errorf("%w: VolumeGroup %q is not bound to any VolumeGroupContent", errVGNotReady, req.Name)
+	}
+
+	if !isVGReady(vg) {
+		return vg, nil, fmt.Errorf("%w: VolumeGroup %q is not ready", errVGNotReady, req.Name)
+	}
+
 	vgc := &volumegroupv1.VolumeGroupContent{}
-	volumeGroupContentName := *vg.Spec.Source.VolumeGroupContentName
 	namespacedVGC := types.NamespacedName{Name: volumeGroupContentName, Namespace: vg.Namespace}
 
 	err = r.Get(ctx, namespacedVGC, vgc)
 	if err != nil {
-		if errors.IsNotFound(err) {
+		if apierrors.IsNotFound(err) {
 			logger.Error(err, "VolumeGroupContent not found", "VolumeGroupContent Name", volumeGroupContentName)
 		}
 
 		return vg, nil, err
 	}
 
+	if vgc.Spec.Source == nil || vgc.Spec.Source.VolumeGroupHandle == "" {
+		return vg, nil, fmt.Errorf("%w: VolumeGroupContent %q has no volume group handle", errVGNotReady, volumeGroupContentName)
+	}
+
 	return vg, vgc, nil
 }
+
+// getVGContentName returns the name of the VolumeGroupContent the VolumeGroup
+// refers to. Pre-provisioned groups name it in the spec, dynamically
+// provisioned groups report it in the status once bound.
+func getVGContentName(vg *volumegroupv1.VolumeGroup) string {
+	if vg.Spec.Source.VolumeGroupContentName != nil && *vg.Spec.Source.VolumeGroupContentName != "" {
+		return *vg.Spec.Source.VolumeGroupContentName
+	}
+
+	if vg.Status.BoundVolumeGroupContentName != nil {
+		return *vg.Status.BoundVolumeGroupContentName
+	}
+
+	return ""
+}
+
+// isVGNotReady returns true if the error was caused by a VolumeGroup that is
+// not yet bound or not ready.
+func isVGNotReady(err error) bool {
+	return errors.Is(err, errVGNotReady)
+}
+
+// vgPredicate filters VolumeGroup events down to the ones that can unblock a
+// pending VolumeReplication or change its members.
+func vgPredicate() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc: func(_ event.CreateEvent) bool {
+			return true
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldVG, ok := e.ObjectOld.(*volumegroupv1.VolumeGroup)
+			if !ok {
+				return false
+			}
+
+			newVG, ok := e.ObjectNew.(*volumegroupv1.VolumeGroup)
+			if !ok {
+				return false
+			}
+
+			return isVGReady(oldVG) != isVGReady(newVG) ||
+				getVGContentName(oldVG) != getVGContentName(newVG) ||
+				!equalPVCNames(oldVG.Status.PVCList, newVG.Status.PVCList)
+		},
+		DeleteFunc: func(_ event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(_ event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// isVGReady returns true if the VolumeGroup reports itself as ready.
+func isVGReady(vg *volumegroupv1.VolumeGroup) bool {
+	return vg.Status.Ready != nil && *vg.Status.Ready
+}
+
+// equalPVCNames returns true if both lists hold the same PVC names.
+func equalPVCNames(a, b []corev1.PersistentVolumeClaim) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	names := make(map[string]bool, len(a))
+	for i := range a {
+		names[a[i].Name] = true
+	}
+
+	for i := range b {
+		if !names[b[i].Name] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// volumeReplicationsForVG maps a VolumeGroup to the VolumeReplications in
+// its namespace that use it as data source.
+func (r *VolumeReplicationReconciler) volumeReplicationsForVG(ctx context.Context, obj client.Object) []reconcile.Request {
+	vrList := &replicationv1alpha1.VolumeReplicationList{}
+
+	err := r.List(ctx, vrList, client.InNamespace(obj.GetNamespace()))
+	if err != nil {
+		r.Log.Error(err, "failed to list volumeReplications", "VGName", obj.GetName(), "Namespace", obj.GetNamespace())
+
+		return nil
+	}
+
+	var requests []reconcile.Request
+
+	for i := range vrList.Items {
+		vr := &vrList.Items[i]
+		if vr.Spec.DataSource.Kind != volumeGroupDataSource || vr.Spec.DataSource.Name != obj.GetName() {
+			continue
+		}
+
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: vr.Name, Namespace: vr.Namespace},
+		})
+	}
+
+	return requests
+}
+
+// hasVGContentHandle returns true if the VolumeGroupContent of the group has
+// a volume group handle, replication can only have been enabled on the group
+// once it has one.
+func (r *VolumeReplicationReconciler) hasVGContentHandle(ctx context.Context, vg *volumegroupv1.VolumeGroup) (bool, error) {
+	vgc := &volumegroupv1.VolumeGroupContent{}
+
+	err := r.Get(ctx, types.NamespacedName{Name: getVGContentName(vg), Namespace: vg.Namespace}, vgc)
+	if err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+
+	return vgc.Spec.Source != nil && vgc.Spec.Source.VolumeGroupHandle != "", nil
+}
+
+// isDeletionForced returns true if the VolumeReplication may be deleted
+// without disabling the replication of its pending or unavailable
+// VolumeGroup.
+func isDeletionForced(instance *replicationv1alpha1.VolumeReplication) bool {
+	forced, err := strconv.ParseBool(instance.GetAnnotations()[forceDeletionAnnotation])
+
+	return err == nil && forced
+}
+
+// isReplicationMaybeEnabled returns true if replication might have been
+// enabled for the VolumeReplication, so that it has to be disabled.
+func isReplicationMaybeEnabled(instance *replicationv1alpha1.VolumeReplication) bool {
+	switch instance.Status.State {
+	case replicationv1alpha1.PrimaryState, replicationv1alpha1.SecondaryState:
+		return true
+	}
+
+	return instance.Status.Enabled != nil
+}
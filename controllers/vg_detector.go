@@ -0,0 +1,133 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	volumegroupv1 "github.com/IBM/csi-volume-group-operator/api/v1"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery"
+)
+
+const (
+	// vgDetectionInterval is the interval at which the VolumeGroup CRDs are
+	// looked up again.
+	vgDetectionInterval = time.Minute
+
+	volumeGroupResource        = "volumegroups"
+	volumeGroupContentResource = "volumegroupcontents"
+)
+
+// volumeGroupDetector detects whether the VolumeGroup CRDs are installed in
+// the cluster. The VolumeGroup data source is only enabled once they are.
+type volumeGroupDetector struct {
+	discovery discovery.DiscoveryInterface
+	log       logr.Logger
+	// onAvailable is called once, the first time the CRDs are detected.
+	onAvailable func() error
+
+	mu        sync.RWMutex
+	available bool
+	started   bool
+}
+
+func newVolumeGroupDetector(dc discovery.DiscoveryInterface, logger logr.Logger, onAvailable func() error) *volumeGroupDetector {
+	return &volumeGroupDetector{
+		discovery:   dc,
+		log:         logger,
+		onAvailable: onAvailable,
+	}
+}
+
+// Available returns true if the VolumeGroup CRDs were found during the last check.
+func (d *volumeGroupDetector) Available() bool {
+	if d == nil {
+		return false
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.available
+}
+
+// check looks up the VolumeGroup CRDs and enables the VolumeGroup data
+// source when they are present.
+func (d *volumeGroupDetector) check() {
+	d.detect()
+	d.enable()
+}
+
+// detect looks up the VolumeGroup CRDs and records whether they are present.
+func (d *volumeGroupDetector) detect() {
+	available, err := resourcesAvailable(d.discovery, volumegroupv1.GroupVersion.String(),
+		volumeGroupResource, volumeGroupContentResource)
+	if err != nil {
+		d.log.Error(err, "failed to discover VolumeGroup resources")
+
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if available != d.available {
+		d.log.Info("VolumeGroup resources availability changed", "Available", available)
+	}
+
+	d.available = available
+}
+
+// enable calls onAvailable the first time the CRDs are detected, it is
+// retried by the next check if it fails.
+func (d *volumeGroupDetector) enable() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.available || d.started {
+		return
+	}
+
+	err := d.onAvailable()
+	if err != nil {
+		d.log.Error(err, "failed to enable VolumeGroup data source")
+
+		return
+	}
+
+	d.started = true
+}
+
+// Start re-checks the VolumeGroup CRDs periodically until the context is done.
+func (d *volumeGroupDetector) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, func(_ context.Context) {
+		d.check()
+	}, vgDetectionInterval)
+
+	return nil
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface, the
+// detection has to run on every replica.
+func (d *volumeGroupDetector) NeedLeaderElection() bool {
+	return false
+}
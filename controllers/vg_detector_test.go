@@ -0,0 +1,97 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	volumegroupv1 "github.com/IBM/csi-volume-group-operator/api/v1"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func TestVolumeGroupDetector(t *testing.T) {
+	t.Parallel()
+
+	dc := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{}}
+	calls := 0
+
+	detector := newVolumeGroupDetector(dc, logf.Log.WithName("vg_detector_test"), func() error {
+		calls++
+
+		return nil
+	})
+
+	// nil detector never reports the VolumeGroup data source as available
+	var nilDetector *volumeGroupDetector
+	require.False(t, nilDetector.Available())
+
+	detector.check()
+	require.False(t, detector.Available())
+	require.Equal(t, 0, calls)
+
+	dc.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: volumegroupv1.GroupVersion.String(),
+			APIResources: []metav1.APIResource{{Name: volumeGroupResource}},
+		},
+	}
+
+	detector.check()
+	require.False(t, detector.Available(), "all VolumeGroup resources must be served")
+
+	dc.Resources[0].APIResources = append(dc.Resources[0].APIResources, metav1.APIResource{Name: volumeGroupContentResource})
+
+	detector.check()
+	require.True(t, detector.Available())
+	require.Equal(t, 1, calls)
+
+	detector.check()
+	require.True(t, detector.Available())
+	require.Equal(t, 1, calls, "the VolumeGroup watch is only started once")
+}
+
+func TestVolumeGroupDetectorBeforeController(t *testing.T) {
+	t.Parallel()
+
+	dc := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{}}
+	dc.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: volumegroupv1.GroupVersion.String(),
+			APIResources: []metav1.APIResource{{Name: volumeGroupResource}, {Name: volumeGroupContentResource}},
+		},
+	}
+	calls := 0
+
+	detector := newVolumeGroupDetector(dc, logf.Log.WithName("vg_detector_test"), func() error {
+		calls++
+
+		return nil
+	})
+
+	// the availability is known before the watch can be started
+	detector.detect()
+	require.True(t, detector.Available())
+	require.Equal(t, 0, calls)
+
+	detector.enable()
+	require.Equal(t, 1, calls)
+}
@@ -0,0 +1,293 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	volumegroupv1 "github.com/IBM/csi-volume-group-operator/api/v1"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	mockVGName            = "test-vg"
+	mockVGCName           = "test-vgc"
+	mockVolumeGroupHandle = "test-volume-group-handle"
+)
+
+var mockVolumeGroupContent = &volumegroupv1.VolumeGroupContent{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:      mockVGCName,
+		Namespace: mockNamespace,
+	},
+	Spec: volumegroupv1.VolumeGroupContentSpec{
+		Source: &volumegroupv1.VolumeGroupContentSource{
+			VolumeGroupHandle: mockVolumeGroupHandle,
+		},
+	},
+}
+
+func newMockVolumeGroup(specContentName, boundContentName *string, ready *bool) *volumegroupv1.VolumeGroup {
+	return &volumegroupv1.VolumeGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mockVGName,
+			Namespace: mockNamespace,
+		},
+		Spec: volumegroupv1.VolumeGroupSpec{
+			Source: volumegroupv1.VolumeGroupSource{
+				VolumeGroupContentName: specContentName,
+			},
+		},
+		Status: volumegroupv1.VolumeGroupStatus{
+			BoundVolumeGroupContentName: boundContentName,
+			Ready:                       ready,
+		},
+	}
+}
+
+func TestGetVGDataSource(t *testing.T) {
+	t.Parallel()
+
+	contentName := mockVGCName
+	ready := true
+	notReady := false
+
+	testcases := []struct {
+		name             string
+		vg               *volumegroupv1.VolumeGroup
+		expectedNotReady bool
+		errorExpected    bool
+	}{
+		{
+			name: "case 1: pre-provisioned volume group",
+			vg:   newMockVolumeGroup(&contentName, nil, &ready),
+		},
+		{
+			name: "case 2: dynamically provisioned volume group bound to content",
+			vg:   newMockVolumeGroup(nil, &contentName, &ready),
+		},
+		{
+			name:             "case 3: dynamically provisioned volume group not yet bound",
+			vg:               newMockVolumeGroup(nil, nil, nil),
+			expectedNotReady: true,
+			errorExpected:    true,
+		},
+		{
+			name:             "case 4: bound volume group not ready",
+			vg:               newMockVolumeGroup(nil, &contentName, &notReady),
+			expectedNotReady: true,
+			errorExpected:    true,
+		},
+	}
+
+	for _, tc := range testcases {
+		testVGC := &volumegroupv1.VolumeGroupContent{}
+		mockVolumeGroupContent.DeepCopyInto(testVGC)
+
+		reconciler := createFakeVolumeReplicationReconciler(t, tc.vg, testVGC)
+
+		namespacedName := types.NamespacedName{Name: mockVGName, Namespace: mockNamespace}
+
+		resultVG, resultVGC, err := reconciler.getVGDataSource(context.TODO(), reconciler.Log, namespacedName)
+		if tc.errorExpected {
+			require.Error(t, err, tc.name)
+			require.Equal(t, tc.expectedNotReady, isVGNotReady(err), tc.name)
+			require.NotNil(t, resultVG, tc.name)
+			require.Nil(t, resultVGC, tc.name)
+		} else {
+			require.NoError(t, err, tc.name)
+			require.NotNil(t, resultVG, tc.name)
+			require.Equal(t, mockVolumeGroupHandle, resultVGC.Spec.Source.VolumeGroupHandle, tc.name)
+		}
+	}
+}
+
+func TestHandlePendingVGDeletion(t *testing.T) {
+	t.Parallel()
+
+	contentName := mockVGCName
+	notReady := false
+	noHandle := mockVolumeGroupContent.DeepCopy()
+	noHandle.Spec.Source = nil
+
+	testcases := []struct {
+		name        string
+		vgc         *volumegroupv1.VolumeGroupContent
+		annotations map[string]string
+		wantBlocked bool
+	}{
+		{
+			name:        "content with handle blocks deletion",
+			vgc:         mockVolumeGroupContent.DeepCopy(),
+			wantBlocked: true,
+		},
+		{
+			name: "content without handle",
+			vgc:  noHandle,
+		},
+		{
+			name:        "forced deletion",
+			vgc:         mockVolumeGroupContent.DeepCopy(),
+			annotations: map[string]string{forceDeletionAnnotation: "true"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			vg := newMockVolumeGroup(nil, &contentName, &notReady)
+			vg.Finalizers = []string{vgReplicationFinalizer}
+
+			volumeReplication := mockVolumeReplicationObj.DeepCopy()
+			volumeReplication.Spec.DataSource.Kind = volumeGroupDataSource
+			volumeReplication.Spec.DataSource.Name = mockVGName
+			volumeReplication.Annotations = tc.annotations
+			volumeReplication.Finalizers = []string{volumeReplicationFinalizer}
+			volumeReplication.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+
+			reconciler := createFakeVolumeReplicationReconciler(t, volumeReplication, vg, tc.vgc)
+
+			ctx := context.TODO()
+			key := types.NamespacedName{Name: volumeReplication.Name, Namespace: volumeReplication.Namespace}
+
+			instance := &replicationv1alpha1.VolumeReplication{}
+			require.NoError(t, reconciler.Get(ctx, key, instance))
+
+			_, _, vgErr := reconciler.getVGDataSource(ctx, reconciler.Log, types.NamespacedName{Name: mockVGName, Namespace: mockNamespace})
+			require.True(t, isVGNotReady(vgErr))
+
+			_, err := reconciler.handlePendingVG(ctx, reconciler.Log, instance, vg, vgErr)
+			require.NoError(t, err)
+
+			err = reconciler.Get(ctx, key, instance)
+			if !tc.wantBlocked {
+				require.True(t, apierrors.IsNotFound(err))
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			pending := findCondition(instance.Status.Conditions, ConditionPending)
+			require.NotNil(t, pending)
+			require.Contains(t, pending.Message, forceDeletionAnnotation)
+		})
+	}
+}
+
+func TestHandleUnavailableVGDeletion(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		name        string
+		state       replicationv1alpha1.State
+		annotations map[string]string
+		wantBlocked bool
+	}{
+		{
+			name:        "enabled replication blocks deletion",
+			state:       replicationv1alpha1.PrimaryState,
+			wantBlocked: true,
+		},
+		{
+			name: "replication never enabled",
+		},
+		{
+			name:        "forced deletion",
+			state:       replicationv1alpha1.PrimaryState,
+			annotations: map[string]string{forceDeletionAnnotation: "true"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			volumeReplication := mockVolumeReplicationObj.DeepCopy()
+			volumeReplication.Spec.DataSource.Kind = volumeGroupDataSource
+			volumeReplication.Spec.DataSource.Name = mockVGName
+			volumeReplication.Annotations = tc.annotations
+			volumeReplication.Finalizers = []string{volumeReplicationFinalizer}
+			volumeReplication.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			volumeReplication.Status.State = tc.state
+
+			member := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "member-0",
+					Namespace:   mockNamespace,
+					Labels:      map[string]string{roleLabel: "primary"},
+					Annotations: map[string]string{volumeReplicationAnnotation: volumeReplication.Name},
+				},
+			}
+
+			reconciler := createFakeVolumeReplicationReconciler(t, volumeReplication, member)
+
+			ctx := context.TODO()
+			key := types.NamespacedName{Name: volumeReplication.Name, Namespace: volumeReplication.Namespace}
+
+			instance := &replicationv1alpha1.VolumeReplication{}
+			require.NoError(t, reconciler.Get(ctx, key, instance))
+
+			_, err := reconciler.handleUnavailableVG(ctx, reconciler.Log, instance)
+			require.NoError(t, err)
+
+			require.NoError(t, reconciler.Get(ctx, client.ObjectKeyFromObject(member), member))
+			require.Equal(t, !tc.wantBlocked, member.Labels[roleLabel] == "")
+
+			err = reconciler.Get(ctx, key, instance)
+			if !tc.wantBlocked {
+				require.True(t, apierrors.IsNotFound(err))
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			completed := findCondition(instance.Status.Conditions, ConditionCompleted)
+			require.NotNil(t, completed)
+			require.Equal(t, VolumeGroupUnavailable, completed.Reason)
+			require.Contains(t, completed.Message, forceDeletionAnnotation)
+		})
+	}
+}
+
+func TestRemoveVolumeGroupUnavailableCondition(t *testing.T) {
+	t.Parallel()
+
+	var conditions []metav1.Condition
+
+	setVolumeGroupUnavailableCondition(&conditions, 1, "not installed")
+	removeVolumeGroupUnavailableCondition(&conditions)
+	require.Empty(t, conditions)
+
+	// the conditions of other failures are kept
+	setFailedPromotionCondition(&conditions, 1)
+	removeVolumeGroupUnavailableCondition(&conditions)
+	require.NotNil(t, findCondition(conditions, ConditionCompleted))
+	require.NotNil(t, findCondition(conditions, ConditionDegraded))
+}
@@ -25,31 +25,40 @@ import (
 
 	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
 	"github.com/csi-addons/volume-replication-operator/controllers/replication"
+	"github.com/csi-addons/volume-replication-operator/controllers/statemachine"
 	grpcClient "github.com/csi-addons/volume-replication-operator/pkg/client"
 	"github.com/csi-addons/volume-replication-operator/pkg/config"
 
 	replicationlib "github.com/csi-addons/spec/lib/go/replication"
 	"github.com/go-logr/logr"
 	"google.golang.org/grpc/codes"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 const (
-	pvcDataSource          = "PersistentVolumeClaim"
-	volumeGroupDataSource  = "VolumeGroup"
-	volumeReplicationClass = "VolumeReplicationClass"
-	volumeReplication      = "VolumeReplication"
+	pvcDataSource         = "PersistentVolumeClaim"
+	volumeGroupDataSource = "VolumeGroup"
+
+	// statusFieldManager is the field manager of the status writes.
+	statusFieldManager = "volume-replication-operator"
 )
 
 var (
@@ -66,11 +75,26 @@ type VolumeReplicationReconciler struct {
 	DriverConfig *config.DriverConfig
 	GRPCClient   *grpcClient.Client
 	Replication  grpcClient.VolumeReplication
+	Recorder     record.EventRecorder
+
+	vgDetector     *volumeGroupDetector
+	requeueBackoff *requeueBackoff
+	rpcBudget      *rpcBudget
+	podExec        podExecutor
+	execHooks      *execHookRunner
 }
 
 // +kubebuilder:rbac:groups=replication.storage.openshift.io,resources=volumereplications,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=replication.storage.openshift.io,resources=volumereplications/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=replication.storage.openshift.io,resources=volumereplications/finalizers,verbs=update
+// +kubebuilder:rbac:groups=replication.storage.openshift.io,resources=volumereplicationclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods/exec,verbs=create
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=volumeattachments,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -90,6 +114,7 @@ func (r *VolumeReplicationReconciler) Reconcile(ctx context.Context, req ctrl.Re
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
 			// Return and don't requeue
 			logger.Info("volumeReplication resource not found")
+			r.requeueBackoff.reset(req.String())
 
 			return reconcile.Result{}, nil
 		}
@@ -97,9 +122,29 @@ func (r *VolumeReplicationReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		return reconcile.Result{}, err
 	}
 
-	// Get VolumeReplicationClass
-	vrcObj, err := r.getVolumeReplicationClass(ctx, logger, instance.Spec.VolumeReplicationClass)
+	if r.isDryRun(instance) {
+		return r.dryRunReconcile(ctx, logger, req, instance)
+	}
+
+	// the dry-run report is only kept while in dry-run mode
+	instance.Status.DryRun = nil
+
+	return r.reconcile(ctx, logger, req, instance)
+}
+
+// reconcile moves the VolumeReplication towards its desired state.
+func (r *VolumeReplicationReconciler) reconcile(
+	ctx context.Context,
+	logger logr.Logger,
+	req ctrl.Request,
+	instance *replicationv1alpha1.VolumeReplication,
+) (ctrl.Result, error) {
+	// the stored status, to tell whether the transition changed it
+	storedStatus := instance.Status.DeepCopy()
+
+	vrcName, owned, err := r.getVolumeReplicationClassName(ctx, logger, instance)
 	if err != nil {
+		logger.Error(err, "failed to choose volumeReplicationClass")
 		setFailureCondition(instance)
 
 		uErr := r.updateReplicationStatus(ctx, instance, logger, getCurrentReplicationState(instance), err.Error())
@@ -110,13 +155,13 @@ func (r *VolumeReplicationReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		return ctrl.Result{}, err
 	}
 
-	if r.DriverConfig.DriverName != vrcObj.Spec.Provisioner {
+	if !owned {
 		return ctrl.Result{}, nil
 	}
 
-	err = validatePrefixedParameters(vrcObj.Spec.Parameters)
+	// Get VolumeReplicationClass
+	vrcObj, err := r.getVolumeReplicationClass(ctx, logger, vrcName)
 	if err != nil {
-		logger.Error(err, "failed to validate parameters of volumeReplicationClass", "VRCName", instance.Spec.VolumeReplicationClass)
 		setFailureCondition(instance)
 
 		uErr := r.updateReplicationStatus(ctx, instance, logger, getCurrentReplicationState(instance), err.Error())
@@ -126,27 +171,79 @@ func (r *VolumeReplicationReconciler) Reconcile(ctx context.Context, req ctrl.Re
 
 		return ctrl.Result{}, err
 	}
-	// remove the prefix keys in volume replication class parameters
-	parameters := filterPrefixedParameters(replicationParameterPrefix, vrcObj.Spec.Parameters)
 
-	// get secret
-	secretName := vrcObj.Spec.Parameters[prefixedReplicationSecretNameKey]
-	secretNamespace := vrcObj.Spec.Parameters[prefixedReplicationSecretNamespaceKey]
+	if r.DriverConfig.DriverName != vrcObj.Spec.Provisioner {
+		return ctrl.Result{}, nil
+	}
 
-	secret := make(map[string]string)
-	if secretName != "" && secretNamespace != "" {
-		secret, err = r.getSecret(ctx, logger, secretName, secretNamespace)
+	// the permissions of the class are checked until replication is enabled
+	// with it, like the webhook the VolumeReplications admitted before the
+	// class was restricted keep using it. The VolumeReplications that are
+	// being deleted may always disable the replication of their volume.
+	admitted := instance.Status.Enabled != nil && instance.Status.VolumeReplicationClass == vrcName
+	if !admitted && instance.GetDeletionTimestamp().IsZero() {
+		reason, msg, denied, err := getClassDenial(ctx, r, instance, vrcObj)
 		if err != nil {
-			setFailureCondition(instance)
+			logger.Error(err, "failed to check the permissions of volumeReplicationClass", "VRCName", vrcName)
 
-			uErr := r.updateReplicationStatus(ctx, instance, logger, getCurrentReplicationState(instance), err.Error())
-			if uErr != nil {
-				logger.Error(uErr, "failed to update volumeReplication status", "VRName", instance.Name)
+			return ctrl.Result{}, err
+		}
+
+		if denied {
+			logger.Info("volumeReplicationClass is not permitted", "Reason", reason, "VRCName", vrcName)
+			setClassNotPermittedCondition(&instance.Status.Conditions, instance.Generation, reason, msg)
+
+			// the class replication is enabled with is kept, so that
+			// the new one is checked again
+			if instance.Status.Enabled == nil {
+				instance.Status.VolumeReplicationClass = vrcName
 			}
 
-			return reconcile.Result{}, err
+			err = r.updateReplicationStatus(ctx, instance, logger, getCurrentReplicationState(instance), msg)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, nil
+		}
+	}
+
+	instance.Status.VolumeReplicationClass = vrcName
+	removeClassNotPermittedCondition(&instance.Status.Conditions)
+
+	// no replication operations are issued while the reconciliation is
+	// paused, the VolumeReplication or its class being updated resumes it.
+	if reason, msg, paused := getPauseReason(instance, vrcObj); paused {
+		logger.Info("reconciliation is paused", "Reason", reason)
+		setPausedCondition(&instance.Status.Conditions, instance.Generation, reason, msg)
+
+		err = r.updateReplicationStatus(ctx, instance, logger, getCurrentReplicationState(instance), msg)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	removePausedCondition(&instance.Status.Conditions)
+
+	err = validatePrefixedParameters(vrcObj.Spec.Parameters)
+	if err != nil {
+		logger.Error(err, "failed to validate parameters of volumeReplicationClass", "VRCName", vrcName)
+		setFailureCondition(instance)
+
+		uErr := r.updateReplicationStatus(ctx, instance, logger, getCurrentReplicationState(instance), err.Error())
+		if uErr != nil {
+			logger.Error(uErr, "failed to update volumeReplication status", "VRName", instance.Name)
 		}
+
+		return ctrl.Result{}, err
 	}
+	// parameters are validated above
+	requeueCfg, _ := parseRequeueConfig(vrcObj.Spec.Parameters)
+
+	// remove the prefix keys in volume replication class parameters
+	parameters := filterPrefixedParameters(replicationParameterPrefix, vrcObj.Spec.Parameters)
 
 	var (
 		volumeHandle string
@@ -178,7 +275,17 @@ func (r *VolumeReplicationReconciler) Reconcile(ctx context.Context, req ctrl.Re
 
 		volumeHandle = pv.Spec.CSI.VolumeHandle
 	case volumeGroupDataSource:
+		if !r.vgDetector.Available() {
+			return r.handleUnavailableVG(ctx, logger, instance)
+		}
+
+		removeVolumeGroupUnavailableCondition(&instance.Status.Conditions)
+
 		vg, vgc, vgErr = r.getVGDataSource(ctx, logger, nameSpacedName)
+		if isVGNotReady(vgErr) {
+			return r.handlePendingVG(ctx, logger, instance, vg, vgErr)
+		}
+
 		if vgErr != nil {
 			logger.Error(vgErr, "failed to get VG", "VGName", instance.Spec.DataSource.Name)
 			setFailureCondition(instance)
@@ -192,6 +299,11 @@ func (r *VolumeReplicationReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		}
 
 		volumeHandle = vgc.Spec.Source.VolumeGroupHandle
+
+		err = r.updateVGMembers(ctx, logger, instance, vg)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
 	default:
 		err = fmt.Errorf("unsupported datasource kind")
 		logger.Error(err, "given kind not supported", "Kind", instance.Spec.DataSource.Kind)
@@ -205,6 +317,34 @@ func (r *VolumeReplicationReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		return ctrl.Result{}, nil
 	}
 
+	removePendingCondition(&instance.Status.Conditions)
+
+	// get secret, its parameters are resolved against the replication source
+	source := metav1.Object(vg)
+	if pvc != nil {
+		source = pvc
+	}
+
+	var secretVersion string
+
+	secret := make(map[string]string)
+
+	secretName, secretNamespace, err := getSecretReference(vrcObj.Spec.Parameters, instance, source)
+	if err == nil && secretName != "" {
+		secret, secretVersion, err = r.getSecret(ctx, logger, secretName, secretNamespace)
+	}
+
+	if err != nil {
+		setFailureCondition(instance)
+
+		uErr := r.updateReplicationStatus(ctx, instance, logger, getCurrentReplicationState(instance), err.Error())
+		if uErr != nil {
+			logger.Error(uErr, "failed to update volumeReplication status", "VRName", instance.Name)
+		}
+
+		return reconcile.Result{}, err
+	}
+
 	logger.Info("volume handle", "VolumeHandleName", volumeHandle)
 	replicationSource := r.getReplicationSource(instance.Spec.DataSource.Kind, volumeHandle)
 	logger.Info("Replication source", "replicationSource", replicationSource)
@@ -265,6 +405,13 @@ func (r *VolumeReplicationReconciler) Reconcile(ctx context.Context, req ctrl.Re
 					return reconcile.Result{}, err
 				}
 			}
+			err = r.syncClaimRoles(ctx, logger, instance)
+			if err != nil {
+				logger.Error(err, "Failed to remove role from PersistentVolumeClaims")
+
+				return reconcile.Result{}, err
+			}
+
 			// once all finalizers have been removed, the object will be
 			// deleted
 			err = r.removeFinalizerFromVR(ctx, logger, instance)
@@ -280,82 +427,100 @@ func (r *VolumeReplicationReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		return ctrl.Result{}, nil
 	}
 
-	instance.Status.LastStartTime = getCurrentTime()
+	// lastStartTime marks the start of the operation for the current
+	// generation, it is written together with the rest of the status.
+	if instance.Status.LastStartTime == nil || instance.Status.ObservedGeneration != instance.Generation {
+		instance.Status.LastStartTime = getCurrentTime()
+	}
+
+	// enable replication only when it was never enabled or its inputs changed
+	inputsHash := enableInputsHash(instance.Spec.DataSource.Kind, volumeHandle, replicationHandle,
+		secretName, secretNamespace, parameters)
+	if needsEnable(instance.Status.Enabled, inputsHash, secretVersion, r.DriverConfig.EnableVerifyInterval, time.Now()) {
+		err = r.enableReplication(logger, replicationSource, replicationHandle, parameters, secret)
+		if err != nil {
+			logger.Error(err, "failed to enable replication")
+			setFailureCondition(instance)
 
-	err = r.Update(ctx, instance)
-	if err != nil {
-		logger.Error(err, "failed to update status")
+			msg := replication.GetMessageFromError(err)
 
-		return reconcile.Result{}, err
+			uErr := r.updateReplicationStatus(ctx, instance, logger, getCurrentReplicationState(instance), msg)
+			if uErr != nil {
+				logger.Error(uErr, "failed to update volumeReplication status", "VRName", instance.Name)
+			}
+
+			return reconcile.Result{}, err
+		}
+
+		instance.Status.Enabled = &replicationv1alpha1.EnabledReplication{
+			InputsHash:            inputsHash,
+			SecretResourceVersion: secretVersion,
+			LastEnableTime:        getCurrentTime(),
+		}
 	}
 
-	// enable replication on every reconcile
-	err = r.enableReplication(logger, replicationSource, replicationHandle, parameters, secret)
+	requeueKey := req.String()
+
+	transition, err := statemachine.Lookup(getCurrentReplicationState(instance), instance.Spec.ReplicationState)
 	if err != nil {
-		logger.Error(err, "failed to enable replication")
+		logger.Error(err, "given volume state is not supported", "ReplicationState", instance.Spec.ReplicationState)
 		setFailureCondition(instance)
 
-		msg := replication.GetMessageFromError(err)
-
-		uErr := r.updateReplicationStatus(ctx, instance, logger, getCurrentReplicationState(instance), msg)
+		uErr := r.updateReplicationStatus(ctx, instance, logger, getCurrentReplicationState(instance), err.Error())
 		if uErr != nil {
 			logger.Error(uErr, "failed to update volumeReplication status", "VRName", instance.Name)
 		}
 
-		return reconcile.Result{}, err
+		return ctrl.Result{}, nil
 	}
 
-	var replicationErr error
+	rejected, err := r.validateTransition(ctx, logger, instance, transition)
+	if rejected || err != nil {
+		return ctrl.Result{}, err
+	}
 
-	var requeueForResync bool
+	waiting, err := r.waitForDependencies(ctx, logger, instance, transition)
+	if waiting || err != nil {
+		return ctrl.Result{}, err
+	}
 
-	switch instance.Spec.ReplicationState {
-	case replicationv1alpha1.Primary:
-		replicationErr = r.markVolumeAsPrimary(instance, logger, replicationSource, replicationHandle, parameters, secret)
+	// polling of secondary and resyncing volumes is limited by the RPC
+	// budget, so that it cannot starve promotions.
+	if rpcs := pollingRPCs(instance); rpcs > 0 {
+		allowed, delay := r.rpcBudget.reserve(rpcs)
+		if !allowed {
+			logger.Info("RPC budget exhausted, delaying replication polling", "RequeueAfter", delay)
 
-	case replicationv1alpha1.Secondary:
-		// For the first time, mark the volume as secondary and requeue the
-		// request. For some storage providers it takes some time to determine
-		// whether the volume need correction example:- correcting split brain.
-		if instance.Status.State != replicationv1alpha1.SecondaryState {
-			replicationErr = r.markVolumeAsSecondary(instance, logger, replicationSource, replicationHandle, parameters, secret)
-			if replicationErr == nil {
-				logger.Info("volume is not ready to use")
-				// set the status.State to secondary as the
-				// instance.Status.State is primary for the first time.
-				err = r.updateReplicationStatus(ctx, instance, logger, getReplicationState(instance), "volume is marked secondary and is degraded")
-				if err != nil {
-					return ctrl.Result{}, err
-				}
+			return ctrl.Result{RequeueAfter: delay}, nil
+		}
+	}
 
-				return ctrl.Result{
-					Requeue: true,
-					// Setting Requeue time for 15 seconds
-					RequeueAfter: time.Second * 15,
-				}, nil
-			}
-		} else {
-			replicationErr = r.markVolumeAsSecondary(instance, logger, replicationSource, replicationHandle, parameters, secret)
-			// resync volume if successfully marked Secondary
-			if replicationErr == nil {
-				requeueForResync, replicationErr = r.resyncVolume(instance, logger, replicationSource, replicationHandle, instance.Spec.AutoResync, parameters, secret)
-			}
+	// the applications are quiesced before the volume is demoted
+	if transition.Demotion {
+		result, stop, hErr := r.runTransitionHooks(ctx, logger, instance, vrcObj, replicationv1alpha1.PreDemoteHook,
+			getCurrentReplicationState(instance))
+		if stop || hErr != nil {
+			return result, hErr
 		}
+	}
 
-	case replicationv1alpha1.Resync:
-		requeueForResync, replicationErr = r.resyncVolume(instance, logger, replicationSource, replicationHandle, true, parameters, secret)
+	requeueForResync, replicationErr := r.runTransitionSteps(instance, logger, transition, replicationSource, replicationHandle, parameters, secret)
 
-	default:
-		replicationErr = fmt.Errorf("unsupported volume state")
-		logger.Error(replicationErr, "given volume state is not supported", "ReplicationState", instance.Spec.ReplicationState)
-		setFailureCondition(instance)
+	// For some storage providers it takes some time to determine whether
+	// a freshly demoted volume needs correction, example:- correcting split
+	// brain. The volume is reported in its target state and requeued.
+	if replicationErr == nil && transition.Settle {
+		logger.Info("volume is not ready to use")
 
-		err = r.updateReplicationStatus(ctx, instance, logger, getCurrentReplicationState(instance), replicationErr.Error())
+		err = r.updateReplicationStatus(ctx, instance, logger, transition.Target, "volume is marked secondary and is degraded")
 		if err != nil {
-			logger.Error(err, "failed to update volumeReplication status", "VRName", instance.Name)
+			return ctrl.Result{}, err
 		}
 
-		return ctrl.Result{}, nil
+		return ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: r.requeueBackoff.next(requeueKey, requeueCfg.demotion, requeueCfg.maxInterval, requeueCfg.jitter),
+		}, nil
 	}
 
 	if replicationErr != nil {
@@ -370,8 +535,9 @@ func (r *VolumeReplicationReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		if instance.Status.State == replicationv1alpha1.SecondaryState {
 			return ctrl.Result{
 				Requeue: true,
-				// in case of any error during secondary state, requeue for every 15 seconds.
-				RequeueAfter: time.Second * 15,
+				// in case of any error during secondary state, requeue with
+				// the secondary error interval of the class.
+				RequeueAfter: r.requeueBackoff.next(requeueKey, requeueCfg.secondaryError, requeueCfg.maxInterval, requeueCfg.jitter),
 			}, nil
 		}
 
@@ -388,10 +554,10 @@ func (r *VolumeReplicationReconciler) Reconcile(ctx context.Context, req ctrl.Re
 
 		return ctrl.Result{
 			Requeue: true,
-			// Setting Requeue time for 30 seconds as the resync can take time
-			// and having default Requeue exponential backoff time can affect
-			// the RTO time.
-			RequeueAfter: time.Second * 30,
+			// Setting Requeue time to the resync interval of the class as the
+			// resync can take time and having default Requeue exponential
+			// backoff time can affect the RTO time.
+			RequeueAfter: r.requeueBackoff.next(requeueKey, requeueCfg.resync, requeueCfg.maxInterval, requeueCfg.jitter),
 		}, nil
 	}
 
@@ -402,27 +568,73 @@ func (r *VolumeReplicationReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		msg = fmt.Sprintf("volume is marked %s", string(instance.Spec.ReplicationState))
 	}
 
-	instance.Status.LastCompletionTime = getCurrentTime()
+	// the workloads are restarted once per promotion from secondary, the
+	// restart is recorded as pending until the post-promote hooks completed.
+	if instance.Spec.RestartWorkloads && transition.From == replicationv1alpha1.SecondaryState &&
+		transition.Target == replicationv1alpha1.PrimaryState &&
+		(instance.Status.WorkloadRestart == nil || instance.Status.WorkloadRestart.ObservedGeneration != instance.Generation) {
+		instance.Status.WorkloadRestart = &replicationv1alpha1.WorkloadRestartStatus{ObservedGeneration: instance.Generation}
+	}
+
+	// the post-promote hooks are run once the volume became primary, and
+	// checked on while they are running.
+	if transition.Target == replicationv1alpha1.PrimaryState &&
+		(transition.From != replicationv1alpha1.PrimaryState || isHookRunning(instance, replicationv1alpha1.PostPromoteHook)) {
+		result, stop, hErr := r.runTransitionHooks(ctx, logger, instance, vrcObj,
+			replicationv1alpha1.PostPromoteHook, transition.Target)
+		if stop || hErr != nil {
+			return result, hErr
+		}
+	}
+
+	if restart := instance.Status.WorkloadRestart; restart != nil && restart.Time == nil &&
+		restart.ObservedGeneration == instance.Generation {
+		touched, wErr := r.restartWorkloads(ctx, logger, instance)
+		if wErr != nil {
+			logger.Error(wErr, "failed to restart workloads")
+
+			uErr := r.updateReplicationStatus(ctx, instance, logger, transition.Target, wErr.Error())
+			if uErr != nil {
+				logger.Error(uErr, "failed to update volumeReplication status", "VRName", instance.Name)
+			}
+
+			return ctrl.Result{}, wErr
+		}
+
+		restart.Time = getCurrentTime()
+		restart.Workloads = touched
+		r.recordEvent(instance, corev1.EventTypeNormal, "WorkloadsRestarted", workloadRestartEventMessage(touched))
+	}
+
+	// the completion time only moves when the transition changed the status,
+	// so that the status of an unchanged volume is not written again
+	completed := instance.Status.DeepCopy()
+	completed.State = transition.Target
+	completed.Message = msg
+	completed.ObservedGeneration = instance.Generation
+	completed.LastCompletionTime = storedStatus.LastCompletionTime
 
-	err = r.updateReplicationStatus(ctx, instance, logger, getReplicationState(instance), msg)
+	if !equality.Semantic.DeepEqual(storedStatus, completed) {
+		instance.Status.LastCompletionTime = getCurrentTime()
+	}
+
+	r.requeueBackoff.reset(requeueKey)
+
+	err = r.updateReplicationStatus(ctx, instance, logger, transition.Target, msg)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
 	logger.Info(msg)
 
-	return ctrl.Result{}, nil
+	// requeue for the drift verification, if enabled
+	return ctrl.Result{RequeueAfter: r.DriverConfig.EnableVerifyInterval}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *VolumeReplicationReconciler) SetupWithManager(mgr ctrl.Manager, cfg *config.DriverConfig) error {
-	err := r.waitForCrds()
-	if err != nil {
-		r.Log.Error(err, "failed to wait for crds")
-
-		return err
-	}
-
+	// registering the VolumeGroup types neither requires the CRDs nor any
+	// permissions, the types are only used once the CRDs are detected.
 	r.Scheme.AddKnownTypes(volumegroupv1.GroupVersion,
 		&volumegroupv1.VolumeGroup{},
 		&volumegroupv1.VolumeGroupContent{},
@@ -431,9 +643,30 @@ func (r *VolumeReplicationReconciler) SetupWithManager(mgr ctrl.Manager, cfg *co
 	)
 	metav1.AddToGroupVersion(r.Scheme, volumegroupv1.GroupVersion)
 
-	pred := predicate.GenerationChangedPredicate{}
-
 	r.DriverConfig = cfg
+	r.requeueBackoff = newRequeueBackoff()
+	r.rpcBudget = newRPCBudget(cfg.PollingRPCQPS, cfg.PollingRPCBurst)
+	r.execHooks = newExecHookRunner()
+
+	if cfg.PodAdmission != "" {
+		// the webhook fails open until the VolumeReplication index is
+		// registered with the controller.
+		err := setupPodWebhook(mgr, r.Log.WithName("podWebhook"), cfg.PodAdmission)
+		if err != nil {
+			r.Log.Error(err, "failed to set up pod webhook")
+
+			return err
+		}
+	}
+
+	if cfg.ClassAdmission {
+		err := setupVolumeReplicationWebhook(mgr, r.Log.WithName("volumeReplicationWebhook"))
+		if err != nil {
+			r.Log.Error(err, "failed to set up volumeReplication webhook")
+
+			return err
+		}
+	}
 
 	gClient, err := grpcClient.New(cfg.DriverEndpoint, cfg.RPCTimeout)
 	if err != nil {
@@ -449,12 +682,323 @@ func (r *VolumeReplicationReconciler) SetupWithManager(mgr ctrl.Manager, cfg *co
 		return err
 	}
 
+	r.podExec, err = newRemotePodExecutor(mgr.GetConfig())
+	if err != nil {
+		r.Log.Error(err, "failed to create pod executor")
+
+		return err
+	}
+
 	r.GRPCClient = gClient
 	r.Replication = grpcClient.NewReplicationClient(r.GRPCClient.Client, cfg.RPCTimeout)
 
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&replicationv1alpha1.VolumeReplication{}).
-		WithEventFilter(pred).Complete(r)
+	dc, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+	if err != nil {
+		r.Log.Error(err, "failed to create discovery client")
+
+		return err
+	}
+
+	// the controller is started in the background once the CRDs are
+	// established, so the manager and its probes start right away.
+	waiter := newCRDWaiter(dc, r.Log.WithName("checkingDependencies"), func() error {
+		return r.setupController(mgr, dc)
+	})
+
+	if cfg.BulkOperations {
+		waiter.resources = append(waiter.resources, volumeReplicationOperationResource)
+	}
+
+	if cfg.ReplicationPolicies {
+		waiter.resources = append(waiter.resources, volumeReplicationPolicyResource)
+	}
+
+	err = mgr.AddReadyzCheck("crds", waiter.ReadyCheck)
+	if err != nil {
+		r.Log.Error(err, "failed to set up CRD ready check")
+
+		return err
+	}
+
+	return mgr.Add(waiter)
+}
+
+// setupController builds the VolumeReplication controller and starts the
+// VolumeGroup detection.
+func (r *VolumeReplicationReconciler) setupController(mgr ctrl.Manager, dc discovery.DiscoveryInterface) error {
+	pred := predicate.GenerationChangedPredicate{}
+
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &corev1.Pod{}, podPVCIndex, podPVCIndexFunc)
+	if err != nil {
+		r.Log.Error(err, "failed to index pods by claim")
+
+		return err
+	}
+
+	err = mgr.GetFieldIndexer().IndexField(context.Background(), &replicationv1alpha1.VolumeReplication{},
+		volumeReplicationPVCIndex, volumeReplicationPVCIndexFunc)
+	if err != nil {
+		r.Log.Error(err, "failed to index volumeReplications by claim")
+
+		return err
+	}
+
+	err = mgr.GetFieldIndexer().IndexField(context.Background(), &replicationv1alpha1.VolumeReplication{},
+		volumeReplicationDependsOnIndex, volumeReplicationDependsOnIndexFunc)
+	if err != nil {
+		r.Log.Error(err, "failed to index volumeReplications by dependency")
+
+		return err
+	}
+
+	err = mgr.GetFieldIndexer().IndexField(context.Background(), &storagev1.VolumeAttachment{},
+		volumeAttachmentPVIndex, volumeAttachmentPVIndexFunc)
+	if err != nil {
+		r.Log.Error(err, "failed to index volumeAttachments by persistentVolume")
+
+		return err
+	}
+
+	err = mgr.GetFieldIndexer().IndexField(context.Background(), &corev1.PersistentVolumeClaim{},
+		pvcStorageClassIndex, pvcStorageClassIndexFunc)
+	if err != nil {
+		r.Log.Error(err, "failed to index persistentVolumeClaims by storageClass")
+
+		return err
+	}
+
+	var c controller.Controller
+
+	// the VolumeGroup CRDs are detected before the controller is started,
+	// so that the VolumeGroup backed VolumeReplications are not rejected
+	// meanwhile. The VolumeGroup watch is only started once the CRDs are
+	// installed, so clusters without them need no VolumeGroup permissions.
+	r.vgDetector = newVolumeGroupDetector(dc, r.Log.WithName("volumeGroupDetector"), func() error {
+		return c.Watch(source.Kind[client.Object](mgr.GetCache(), &volumegroupv1.VolumeGroup{},
+			handler.EnqueueRequestsFromMapFunc(r.volumeReplicationsForVG), vgPredicate()))
+	})
+	r.vgDetector.detect()
+
+	c, err = ctrl.NewControllerManagedBy(mgr).
+		// annotation changes toggle the dry-run mode of a VolumeReplication
+		For(&replicationv1alpha1.VolumeReplication{}, builder.WithPredicates(
+			predicate.Or[client.Object](pred, predicate.AnnotationChangedPredicate{}))).
+		// the completion of Job hooks resumes the transition
+		Owns(&batchv1.Job{}).
+		// state changes release the transitions held by dependencies
+		Watches(&replicationv1alpha1.VolumeReplication{},
+			handler.EnqueueRequestsFromMapFunc(r.volumeReplicationsForDependency),
+			builder.WithPredicates(dependencyPredicate())).
+		// label and annotation changes can change the chosen classes
+		Watches(&replicationv1alpha1.VolumeReplicationClass{},
+			handler.EnqueueRequestsFromMapFunc(r.volumeReplicationsForClass),
+			builder.WithPredicates(predicate.Or[client.Object](pred,
+				predicate.LabelChangedPredicate{}, predicate.AnnotationChangedPredicate{}))).
+		// deletions free places in the quota of the classes
+		Watches(&replicationv1alpha1.VolumeReplication{},
+			handler.EnqueueRequestsFromMapFunc(r.volumeReplicationsForQuota),
+			builder.WithPredicates(classQuotaPredicate())).
+		// label changes allow or deny the classes in the namespace
+		Watches(&corev1.Namespace{},
+			handler.EnqueueRequestsFromMapFunc(r.volumeReplicationsForNamespace),
+			builder.WithPredicates(predicate.LabelChangedPredicate{})).
+		Watches(&corev1.Pod{},
+			handler.EnqueueRequestsFromMapFunc(r.volumeReplicationsForPod),
+			builder.WithPredicates(podPredicate())).
+		Watches(&storagev1.VolumeAttachment{},
+			handler.EnqueueRequestsFromMapFunc(r.volumeReplicationsForVolumeAttachment),
+			builder.WithPredicates(volumeAttachmentPredicate())).
+		Build(r)
+	if err != nil {
+		r.Log.Error(err, "failed to create controller")
+
+		return err
+	}
+
+	r.vgDetector.enable()
+
+	defaultState := replicationv1alpha1.ReplicationState(r.DriverConfig.DefaultReplicationState)
+	if defaultState == "" {
+		defaultState = replicationv1alpha1.Primary
+	}
+
+	err = (&autoCreateReconciler{
+		Client:       r.Client,
+		scheme:       r.Scheme,
+		log:          r.Log.WithName("autoCreate"),
+		recorder:     r.Recorder,
+		driverName:   r.DriverConfig.DriverName,
+		defaultState: defaultState,
+	}).setupWithManager(mgr)
+	if err != nil {
+		r.Log.Error(err, "failed to create volumeReplication auto-create controller")
+
+		return err
+	}
+
+	if r.DriverConfig.ReplicationPolicies {
+		err = (&volumeReplicationPolicyReconciler{
+			Client:     r.Client,
+			scheme:     r.Scheme,
+			log:        r.Log.WithName("VolumeReplicationPolicy"),
+			recorder:   r.Recorder,
+			driverName: r.DriverConfig.DriverName,
+		}).setupWithManager(mgr)
+		if err != nil {
+			r.Log.Error(err, "failed to create volumeReplicationPolicy controller")
+
+			return err
+		}
+	}
+
+	if r.DriverConfig.BulkOperations {
+		err = (&volumeReplicationOperationReconciler{
+			Client:   r.Client,
+			log:      r.Log.WithName("VolumeReplicationOperation"),
+			recorder: r.Recorder,
+		}).setupWithManager(mgr)
+		if err != nil {
+			r.Log.Error(err, "failed to create volumeReplicationOperation controller")
+
+			return err
+		}
+	}
+
+	return mgr.Add(r.vgDetector)
+}
+
+// handleUnavailableVG rejects VolumeGroup backed VolumeReplications while the
+// VolumeGroup CRDs are not installed. The VolumeGroup watch triggers a new
+// reconcile once they are detected. The deletion of a VolumeReplication that
+// might have enabled replication waits for them as well, unless forced.
+func (r *VolumeReplicationReconciler) handleUnavailableVG(
+	ctx context.Context,
+	logger logr.Logger,
+	instance *replicationv1alpha1.VolumeReplication,
+) (ctrl.Result, error) {
+	msg := "VolumeGroup data source is not supported as the VolumeGroup CRDs are not installed"
+	logger.Info(msg, "VGName", instance.Spec.DataSource.Name)
+
+	if !instance.GetDeletionTimestamp().IsZero() {
+		enabled := isReplicationMaybeEnabled(instance)
+		if enabled && !isDeletionForced(instance) {
+			msg = fmt.Sprintf("deletion waits for the VolumeGroup CRDs to be installed to disable replication, "+
+				"annotate with %s=true to delete without disabling it", forceDeletionAnnotation)
+			setVolumeGroupUnavailableCondition(&instance.Status.Conditions, instance.Generation, msg)
+
+			return ctrl.Result{}, r.updateReplicationStatus(ctx, instance, logger, getCurrentReplicationState(instance), msg)
+		}
+
+		if enabled {
+			logger.Info("deleting volumeReplication without disabling replication of unavailable volumeGroup",
+				"VGName", instance.Spec.DataSource.Name)
+			r.recordEvent(instance, corev1.EventTypeWarning, "DeletionForced",
+				fmt.Sprintf("deleted without disabling the replication of VolumeGroup %q as the VolumeGroup CRDs are not installed",
+					instance.Spec.DataSource.Name))
+		}
+
+		err := r.syncClaimRoles(ctx, logger, instance)
+		if err != nil {
+			logger.Error(err, "Failed to remove role from PersistentVolumeClaims")
+
+			return reconcile.Result{}, err
+		}
+
+		err = r.removeFinalizerFromVR(ctx, logger, instance)
+		if err != nil {
+			logger.Error(err, "Failed to remove VolumeReplication finalizer")
+
+			return reconcile.Result{}, err
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	setVolumeGroupUnavailableCondition(&instance.Status.Conditions, instance.Generation, msg)
+
+	err := r.updateReplicationStatus(ctx, instance, logger, getCurrentReplicationState(instance), msg)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// handlePendingVG records that the VolumeGroup data source is not ready yet.
+// The request is not requeued, the VolumeGroup watch triggers a new reconcile
+// once the group becomes ready. The deletion of the VolumeReplication waits
+// for the group as well if replication might have been enabled on it, unless
+// forced.
+func (r *VolumeReplicationReconciler) handlePendingVG(
+	ctx context.Context,
+	logger logr.Logger,
+	instance *replicationv1alpha1.VolumeReplication,
+	vg *volumegroupv1.VolumeGroup,
+	vgErr error,
+) (ctrl.Result, error) {
+	logger.Info("volumeGroup is not ready, waiting", "VGName", instance.Spec.DataSource.Name, "Reason", vgErr.Error())
+
+	if !instance.GetDeletionTimestamp().IsZero() {
+		// a group whose content has no handle never had replication
+		// enabled, so there is nothing to disable.
+		hasHandle := false
+		if getVGContentName(vg) != "" {
+			var err error
+
+			hasHandle, err = r.hasVGContentHandle(ctx, vg)
+			if err != nil {
+				logger.Error(err, "failed to get VolumeGroupContent", "VGName", vg.Name)
+
+				return ctrl.Result{}, err
+			}
+		}
+
+		if hasHandle && !isDeletionForced(instance) {
+			msg := fmt.Sprintf("deletion waits for the VolumeGroup to be ready to disable replication, "+
+				"annotate with %s=true to delete without disabling it: %s", forceDeletionAnnotation, vgErr.Error())
+			setPendingCondition(&instance.Status.Conditions, instance.Generation, msg)
+
+			return ctrl.Result{}, r.updateReplicationStatus(ctx, instance, logger, getCurrentReplicationState(instance), msg)
+		}
+
+		if hasHandle {
+			logger.Info("deleting volumeReplication without disabling replication of pending volumeGroup", "VGName", vg.Name)
+			r.recordEvent(instance, corev1.EventTypeWarning, "DeletionForced",
+				fmt.Sprintf("deleted without disabling the replication of VolumeGroup %q that is not ready", vg.Name))
+		}
+
+		err := r.removeFinalizerFromVG(ctx, logger, vg)
+		if err != nil {
+			logger.Error(err, "Failed to remove VolumeGroup finalizer")
+
+			return reconcile.Result{}, err
+		}
+
+		err = r.syncClaimRoles(ctx, logger, instance)
+		if err != nil {
+			logger.Error(err, "Failed to remove role from PersistentVolumeClaims")
+
+			return reconcile.Result{}, err
+		}
+
+		err = r.removeFinalizerFromVR(ctx, logger, instance)
+		if err != nil {
+			logger.Error(err, "Failed to remove VolumeReplication finalizer")
+
+			return reconcile.Result{}, err
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	setPendingCondition(&instance.Status.Conditions, instance.Generation, vgErr.Error())
+
+	err := r.updateReplicationStatus(ctx, instance, logger, getCurrentReplicationState(instance), vgErr.Error())
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
 }
 
 func (r *VolumeReplicationReconciler) updateReplicationStatus(
@@ -468,7 +1012,27 @@ func (r *VolumeReplicationReconciler) updateReplicationStatus(
 	instance.Status.Message = message
 	instance.Status.ObservedGeneration = instance.Generation
 
-	err := r.Status().Update(ctx, instance)
+	stored := &replicationv1alpha1.VolumeReplication{}
+
+	err := r.Get(ctx, client.ObjectKeyFromObject(instance), stored)
+	if err != nil {
+		logger.Error(err, "failed to get VolumeReplication")
+
+		return err
+	}
+
+	// the claims are synced before the status is written, so that a failed
+	// sync is retried by the next reconcile.
+	if claimRolesChanged(stored, instance) {
+		err = r.syncClaimRoles(ctx, logger, instance)
+		if err != nil {
+			logger.Error(err, "failed to set role on PersistentVolumeClaims")
+
+			return err
+		}
+	}
+
+	err = r.patchReplicationStatus(ctx, instance)
 	if err != nil {
 		logger.Error(err, "failed to update status")
 
@@ -478,49 +1042,111 @@ func (r *VolumeReplicationReconciler) updateReplicationStatus(
 	return nil
 }
 
-func (r *VolumeReplicationReconciler) waitForCrds() error {
-	logger := r.Log.WithName("checkingDependencies")
+// patchReplicationStatus writes the status of the instance to the status
+// subresource with a merge patch. The write is skipped if the status is
+// unchanged. The whole status is replaced, so it is not retried on conflicts:
+// the reconcile is requeued to compute the status of the latest object.
+func (r *VolumeReplicationReconciler) patchReplicationStatus(ctx context.Context, instance *replicationv1alpha1.VolumeReplication) error {
+	latest := &replicationv1alpha1.VolumeReplication{}
 
-	err := r.waitForVolumeReplicationResource(logger, volumeReplicationClass)
+	err := r.Get(ctx, client.ObjectKeyFromObject(instance), latest)
 	if err != nil {
-		logger.Error(err, "failed to wait for VolumeReplicationClass CRD")
-
 		return err
 	}
 
-	err = r.waitForVolumeReplicationResource(logger, volumeReplication)
-	if err != nil {
-		logger.Error(err, "failed to wait for VolumeReplication CRD")
+	if latest.ResourceVersion != instance.ResourceVersion {
+		return errors.NewConflict(replicationv1alpha1.GroupVersion.WithResource("volumereplications").GroupResource(),
+			instance.Name, fmt.Errorf("the object has been modified since resourceVersion %s", instance.ResourceVersion))
+	}
+
+	if equality.Semantic.DeepEqual(latest.Status, instance.Status) {
+		return nil
+	}
+
+	patched := latest.DeepCopy()
+	instance.Status.DeepCopyInto(&patched.Status)
 
+	err = r.Status().Patch(ctx, patched,
+		client.MergeFromWithOptions(latest, client.MergeFromWithOptimisticLock{}),
+		client.FieldOwner(statusFieldManager))
+	if err != nil {
 		return err
 	}
 
+	instance.ResourceVersion = patched.ResourceVersion
+
 	return nil
 }
 
-func (r *VolumeReplicationReconciler) waitForVolumeReplicationResource(logger logr.Logger, resourceName string) error {
-	unstructuredResource := &unstructured.UnstructuredList{}
-	unstructuredResource.SetGroupVersionKind(schema.GroupVersionKind{
-		Group:   replicationv1alpha1.GroupVersion.Group,
-		Kind:    resourceName,
-		Version: replicationv1alpha1.GroupVersion.Version,
-	})
+// patchStatus applies mutate to the status of the latest object and writes
+// it to the status subresource with a merge patch. The write is skipped if
+// the status is unchanged, and retried on conflicts.
+func (r *VolumeReplicationReconciler) patchStatus(
+	ctx context.Context,
+	instance *replicationv1alpha1.VolumeReplication,
+	mutate func(status *replicationv1alpha1.VolumeReplicationStatus),
+) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &replicationv1alpha1.VolumeReplication{}
 
-	for {
-		err := r.List(context.TODO(), unstructuredResource)
-		if err == nil {
+		err := r.Get(ctx, client.ObjectKeyFromObject(instance), latest)
+		if err != nil {
+			return err
+		}
+
+		patched := latest.DeepCopy()
+		mutate(&patched.Status)
+
+		if equality.Semantic.DeepEqual(latest.Status, patched.Status) {
 			return nil
 		}
-		// return errors other than NoMatch
-		if !meta.IsNoMatchError(err) {
-			logger.Error(err, "got an unexpected error while waiting for resource", "Resource", resourceName)
 
+		err = r.Status().Patch(ctx, patched,
+			client.MergeFromWithOptions(latest, client.MergeFromWithOptimisticLock{}),
+			client.FieldOwner(statusFieldManager))
+		if err != nil {
 			return err
 		}
 
-		logger.Info("resource does not exist", "Resource", resourceName)
-		time.Sleep(5 * time.Second)
+		instance.ResourceVersion = patched.ResourceVersion
+
+		return nil
+	})
+}
+
+// runTransitionSteps runs the replication operations of the transition in
+// order, stopping at the first failure. It returns true if the volume needs
+// to be requeued as its resync is not complete.
+func (r *VolumeReplicationReconciler) runTransitionSteps(volumeReplicationObject *replicationv1alpha1.VolumeReplication,
+	logger logr.Logger, transition statemachine.Transition, replicationSource *replicationlib.ReplicationSource,
+	replicationID string, parameters, secrets map[string]string,
+) (bool, error) {
+	var requeueForResync bool
+
+	for _, step := range transition.Steps {
+		var err error
+
+		switch step {
+		case statemachine.Promote:
+			err = r.markVolumeAsPrimary(volumeReplicationObject, logger, replicationSource, replicationID, parameters, secrets)
+		case statemachine.Demote:
+			err = r.markVolumeAsSecondary(volumeReplicationObject, logger, replicationSource, replicationID, parameters, secrets)
+		case statemachine.Resync:
+			requeueForResync, err = r.resyncVolume(volumeReplicationObject, logger, replicationSource, replicationID,
+				volumeReplicationObject.Spec.AutoResync, parameters, secrets)
+		case statemachine.ForceResync:
+			requeueForResync, err = r.resyncVolume(volumeReplicationObject, logger, replicationSource, replicationID,
+				true, parameters, secrets)
+		default:
+			err = fmt.Errorf("unsupported transition step %q", step)
+		}
+
+		if err != nil {
+			return false, err
+		}
 	}
+
+	return requeueForResync, nil
 }
 
 // markVolumeAsPrimary defines and runs a set of tasks required to mark a volume as primary.
@@ -681,7 +1307,7 @@ func (r *VolumeReplicationReconciler) disableVolumeReplication(logger logr.Logge
 	return nil
 }
 
-// enableReplication enable volume replication on the first reconcile.
+// enableReplication enables volume replication.
 func (r *VolumeReplicationReconciler) enableReplication(logger logr.Logger, replicationSource *replicationlib.ReplicationSource, replicationID string,
 	parameters, secrets map[string]string,
 ) error {
@@ -743,17 +1369,20 @@ func (r *VolumeReplicationReconciler) getReplicationSource(kind string, volumeHa
 	}
 }
 
-func getReplicationState(instance *replicationv1alpha1.VolumeReplication) replicationv1alpha1.State {
+// pollingRPCs returns the number of RPCs issued for polling a secondary or
+// resyncing volume, or zero if the reconcile does not poll.
+func pollingRPCs(instance *replicationv1alpha1.VolumeReplication) int {
 	switch instance.Spec.ReplicationState {
-	case replicationv1alpha1.Primary:
-		return replicationv1alpha1.PrimaryState
 	case replicationv1alpha1.Secondary:
-		return replicationv1alpha1.SecondaryState
+		if instance.Status.State == replicationv1alpha1.SecondaryState {
+			// demote and resync
+			return 2
+		}
 	case replicationv1alpha1.Resync:
-		return replicationv1alpha1.SecondaryState
+		return 1
 	}
 
-	return replicationv1alpha1.UnknownState
+	return 0
 }
 
 func getCurrentReplicationState(instance *replicationv1alpha1.VolumeReplication) replicationv1alpha1.State {
@@ -775,8 +1404,10 @@ func setFailureCondition(instance *replicationv1alpha1.VolumeReplication) {
 	}
 }
 
+// getCurrentTime returns the current time truncated to the precision it is
+// serialized with, so that unchanged statuses compare equal.
 func getCurrentTime() *metav1.Time {
-	metav1NowTime := metav1.NewTime(time.Now())
+	metav1NowTime := metav1.NewTime(time.Now().Truncate(time.Second))
 
 	return &metav1NowTime
 }
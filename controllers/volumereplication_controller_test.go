@@ -0,0 +1,223 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+	"github.com/csi-addons/volume-replication-operator/pkg/client/fake"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconcileReplicationStates(t *testing.T) {
+	t.Parallel()
+
+	volumeReplication := mockVolumeReplicationObj.DeepCopy()
+	volumeReplication.Spec.DataSource.Kind = pvcDataSource
+	volumeReplication.Spec.ReplicationState = replicationv1alpha1.Primary
+
+	reconciler := createFakeVolumeReplicationReconciler(t,
+		volumeReplication,
+		mockVolumeReplicationClassObj.DeepCopy(),
+		mockPersistentVolumeClaim.DeepCopy(),
+		mockPersistentVolume.DeepCopy(),
+	)
+	driver := fake.NewStatefulReplicationClient(1)
+	reconciler.Replication = driver
+
+	key := types.NamespacedName{Name: volumeReplication.Name, Namespace: volumeReplication.Namespace}
+	req := ctrl.Request{NamespacedName: key}
+	source := fake.VolumeSource(mockVolumeHandle)
+
+	_, err := reconciler.Reconcile(context.TODO(), req)
+	require.NoError(t, err)
+
+	latest := &replicationv1alpha1.VolumeReplication{}
+	require.NoError(t, reconciler.Get(context.TODO(), key, latest))
+	require.Equal(t, replicationv1alpha1.PrimaryState, latest.Status.State)
+
+	state, ok := driver.State(source)
+	require.True(t, ok)
+	require.Equal(t, fake.Primary, state.Role)
+
+	// demotion is requeued once before the volume is reported secondary
+	latest.Spec.ReplicationState = replicationv1alpha1.Secondary
+	latest.Generation++
+	require.NoError(t, reconciler.Update(context.TODO(), latest))
+
+	result, err := reconciler.Reconcile(context.TODO(), req)
+	require.NoError(t, err)
+	require.NotZero(t, result.RequeueAfter)
+
+	_, err = reconciler.Reconcile(context.TODO(), req)
+	require.NoError(t, err)
+
+	require.NoError(t, reconciler.Get(context.TODO(), key, latest))
+	require.Equal(t, replicationv1alpha1.SecondaryState, latest.Status.State)
+
+	state, _ = driver.State(source)
+	require.Equal(t, fake.Secondary, state.Role)
+	require.Equal(t, []fake.Operation{
+		fake.EnableVolumeReplication,
+		fake.PromoteVolume,
+		fake.DemoteVolume,
+		fake.DemoteVolume,
+		fake.ResyncVolume,
+	}, driver.Calls())
+
+	// a failed promotion of a secondary volume is reported and requeued
+	driver.InjectFault(fake.Fault{Operation: fake.PromoteVolume, Code: codes.Internal, Message: "array is busy"})
+
+	require.NoError(t, reconciler.Get(context.TODO(), key, latest))
+	latest.Spec.ReplicationState = replicationv1alpha1.Primary
+	latest.Generation++
+	require.NoError(t, reconciler.Update(context.TODO(), latest))
+
+	result, err = reconciler.Reconcile(context.TODO(), req)
+	require.NoError(t, err)
+	require.NotZero(t, result.RequeueAfter)
+
+	require.NoError(t, reconciler.Get(context.TODO(), key, latest))
+	require.Equal(t, "array is busy", latest.Status.Message)
+}
+
+func TestReconcileRejectsUnsafeTransition(t *testing.T) {
+	t.Parallel()
+
+	volumeReplication := mockVolumeReplicationObj.DeepCopy()
+	volumeReplication.Spec.DataSource.Kind = pvcDataSource
+	volumeReplication.Spec.ReplicationState = replicationv1alpha1.Resync
+	volumeReplication.Status.State = replicationv1alpha1.PrimaryState
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-0", Namespace: mockNamespace},
+		Spec: corev1.PodSpec{
+			Volumes: []corev1.Volume{{
+				Name: "data",
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: mockPVCName},
+				},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+
+	reconciler := createFakeVolumeReplicationReconciler(t,
+		volumeReplication,
+		pod,
+		mockVolumeReplicationClassObj.DeepCopy(),
+		mockPersistentVolumeClaim.DeepCopy(),
+		mockPersistentVolume.DeepCopy(),
+	)
+	driver := fake.NewStatefulReplicationClient(1)
+	reconciler.Replication = driver
+
+	key := types.NamespacedName{Name: volumeReplication.Name, Namespace: volumeReplication.Namespace}
+
+	result, err := reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	require.Equal(t, ctrl.Result{}, result)
+
+	latest := &replicationv1alpha1.VolumeReplication{}
+	require.NoError(t, reconciler.Get(context.TODO(), key, latest))
+
+	completed := findCondition(latest.Status.Conditions, ConditionCompleted)
+	require.NotNil(t, completed)
+	require.Equal(t, UnsafeTransition, completed.Reason)
+	require.Contains(t, completed.Message, "pod/app-0")
+	require.Equal(t, replicationv1alpha1.PrimaryState, latest.Status.State)
+	require.NotContains(t, driver.Calls(), fake.ResyncVolume)
+
+	// the pod releasing the claim triggers a new reconcile
+	requests := reconciler.volumeReplicationsForPod(context.TODO(), pod)
+	require.Equal(t, []reconcile.Request{{NamespacedName: key}}, requests)
+}
+
+func TestReconcileBlocksDemotionInUse(t *testing.T) {
+	t.Parallel()
+
+	volumeReplication := mockVolumeReplicationObj.DeepCopy()
+	volumeReplication.Spec.DataSource.Kind = pvcDataSource
+	volumeReplication.Spec.ReplicationState = replicationv1alpha1.Secondary
+	volumeReplication.Status.State = replicationv1alpha1.PrimaryState
+
+	pv := mockPersistentVolume.DeepCopy()
+	pv.Spec.ClaimRef = &corev1.ObjectReference{Name: mockPVCName, Namespace: mockNamespace}
+
+	pvName := mockPVName
+	attachment := &storagev1.VolumeAttachment{
+		ObjectMeta: metav1.ObjectMeta{Name: "csi-attachment"},
+		Spec: storagev1.VolumeAttachmentSpec{
+			Attacher: "test-driver",
+			NodeName: "node-0",
+			Source:   storagev1.VolumeAttachmentSource{PersistentVolumeName: &pvName},
+		},
+	}
+
+	reconciler := createFakeVolumeReplicationReconciler(t,
+		volumeReplication,
+		attachment,
+		mockVolumeReplicationClassObj.DeepCopy(),
+		mockPersistentVolumeClaim.DeepCopy(),
+		pv,
+	)
+	driver := fake.NewStatefulReplicationClient(1)
+	reconciler.Replication = driver
+
+	key := types.NamespacedName{Name: volumeReplication.Name, Namespace: volumeReplication.Namespace}
+
+	result, err := reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	require.Equal(t, ctrl.Result{}, result)
+
+	latest := &replicationv1alpha1.VolumeReplication{}
+	require.NoError(t, reconciler.Get(context.TODO(), key, latest))
+
+	blocked := findCondition(latest.Status.Conditions, ConditionDemotionBlocked)
+	require.NotNil(t, blocked)
+	require.Equal(t, VolumeInUse, blocked.Reason)
+	require.Equal(t, metav1.ConditionTrue, blocked.Status)
+	require.Contains(t, blocked.Message, "volumeattachment/csi-attachment")
+	require.Equal(t, replicationv1alpha1.PrimaryState, latest.Status.State)
+	require.NotContains(t, driver.Calls(), fake.DemoteVolume)
+
+	// the detach of the volume triggers a new reconcile
+	requests := reconciler.volumeReplicationsForVolumeAttachment(context.TODO(), attachment)
+	require.Equal(t, []reconcile.Request{{NamespacedName: key}}, requests)
+
+	// the override annotation allows the demotion
+	latest.Annotations = map[string]string{forceDemotionAnnotation: "true"}
+	require.NoError(t, reconciler.Update(context.TODO(), latest))
+
+	_, err = reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	require.NoError(t, reconciler.Get(context.TODO(), key, latest))
+	require.Nil(t, findCondition(latest.Status.Conditions, ConditionDemotionBlocked))
+	require.Equal(t, replicationv1alpha1.SecondaryState, latest.Status.State)
+	require.Contains(t, driver.Calls(), fake.DemoteVolume)
+}
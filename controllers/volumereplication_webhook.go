@@ -0,0 +1,126 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:path=/validate-replication-storage-openshift-io-v1alpha1-volumereplication,mutating=false,failurePolicy=ignore,sideEffects=None,groups=replication.storage.openshift.io,resources=volumereplications,verbs=create;update,versions=v1alpha1,name=vvolumereplication.replication.storage.openshift.io,admissionReviewVersions=v1
+
+// volumeReplicationValidator rejects the VolumeReplications using a class
+// that their namespace is not permitted to use. The reconciler enforces the
+// same rules, the webhook only reports them earlier.
+type volumeReplicationValidator struct {
+	client.Reader
+
+	log logr.Logger
+}
+
+var _ admission.CustomValidator = &volumeReplicationValidator{}
+
+// setupVolumeReplicationWebhook registers the VolumeReplication validating
+// webhook.
+func setupVolumeReplicationWebhook(mgr ctrl.Manager, log logr.Logger) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&replicationv1alpha1.VolumeReplication{}).
+		WithValidator(&volumeReplicationValidator{Reader: mgr.GetClient(), log: log}).
+		Complete()
+}
+
+// ValidateCreate rejects a VolumeReplication using a class it is not
+// permitted to use.
+func (v *volumeReplicationValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	vr, ok := obj.(*replicationv1alpha1.VolumeReplication)
+	if !ok {
+		return nil, fmt.Errorf("expected a volumeReplication but got %T", obj)
+	}
+
+	return nil, v.validateClass(ctx, vr)
+}
+
+// ValidateUpdate only validates the changes of the class, so that the
+// VolumeReplications admitted before the class was restricted can still be
+// failed over and deleted. The reconciler likewise only checks the class
+// until replication is enabled with it.
+func (v *volumeReplicationValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldVR, ok := oldObj.(*replicationv1alpha1.VolumeReplication)
+	if !ok {
+		return nil, fmt.Errorf("expected a volumeReplication but got %T", oldObj)
+	}
+
+	vr, ok := newObj.(*replicationv1alpha1.VolumeReplication)
+	if !ok {
+		return nil, fmt.Errorf("expected a volumeReplication but got %T", newObj)
+	}
+
+	if oldVR.Spec.VolumeReplicationClass == vr.Spec.VolumeReplicationClass || !vr.GetDeletionTimestamp().IsZero() {
+		return nil, nil
+	}
+
+	return nil, v.validateClass(ctx, vr)
+}
+
+// ValidateDelete admits all deletions.
+func (v *volumeReplicationValidator) ValidateDelete(context.Context, runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateClass returns an error if the VolumeReplication may not use its
+// class. The classes that are chosen by the operator are only checked by the
+// reconciler.
+func (v *volumeReplicationValidator) validateClass(ctx context.Context, vr *replicationv1alpha1.VolumeReplication) error {
+	if vr.Spec.VolumeReplicationClass == "" {
+		return nil
+	}
+
+	vrc := &replicationv1alpha1.VolumeReplicationClass{}
+
+	err := v.Get(ctx, types.NamespacedName{Name: vr.Spec.VolumeReplicationClass}, vrc)
+	if err != nil {
+		// the webhook fails open, as does its failure policy, a missing
+		// class is reported by the reconciler.
+		if client.IgnoreNotFound(err) != nil {
+			v.log.Error(err, "failed to get volumeReplicationClass", "VRCName", vr.Spec.VolumeReplicationClass)
+		}
+
+		return nil
+	}
+
+	_, msg, denied, err := getClassDenial(ctx, v, vr, vrc)
+	if err != nil {
+		v.log.Error(err, "failed to check the permissions of volumeReplicationClass", "VRCName", vrc.Name)
+
+		return nil
+	}
+
+	if denied {
+		return fmt.Errorf("%s: %s", ConditionClassNotPermitted, msg)
+	}
+
+	return nil
+}
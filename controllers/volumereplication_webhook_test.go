@@ -0,0 +1,75 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func TestVolumeReplicationValidator(t *testing.T) {
+	t.Parallel()
+
+	vrc := mockVolumeReplicationClassObj.DeepCopy()
+	vrc.Spec.MaxVolumeReplicationsPerNamespace = ptr.To[int32](1)
+
+	existing := mockVolumeReplicationObj.DeepCopy()
+
+	reconciler := createFakeVolumeReplicationReconciler(t, vrc, existing)
+	validator := &volumeReplicationValidator{Reader: reconciler.Client, log: logf.Log}
+
+	ctx := context.TODO()
+
+	// the quota of the namespace is used by the existing volumeReplication
+	created := mockVolumeReplicationObj.DeepCopy()
+	created.Name = "created"
+
+	_, err := validator.ValidateCreate(ctx, created)
+	require.ErrorContains(t, err, "the maximum is 1")
+
+	// the classes chosen by the operator and missing classes are admitted
+	created.Spec.VolumeReplicationClass = ""
+
+	_, err = validator.ValidateCreate(ctx, created)
+	require.NoError(t, err)
+
+	created.Spec.VolumeReplicationClass = "missing"
+
+	_, err = validator.ValidateCreate(ctx, created)
+	require.NoError(t, err)
+
+	// updates are only validated when the class changes
+	updated := existing.DeepCopy()
+	updated.Spec.ReplicationState = replicationv1alpha1.Secondary
+	vrc.Spec.AllowedNamespaces = &replicationv1alpha1.AllowedNamespaces{}
+	require.NoError(t, reconciler.Update(ctx, vrc))
+
+	_, err = validator.ValidateUpdate(ctx, existing, updated)
+	require.NoError(t, err)
+
+	moved := created.DeepCopy()
+	moved.Spec.VolumeReplicationClass = vrc.Name
+
+	_, err = validator.ValidateUpdate(ctx, created, moved)
+	require.ErrorContains(t, err, ConditionClassNotPermitted)
+}
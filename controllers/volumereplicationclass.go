@@ -18,12 +18,24 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"sort"
 
 	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
 
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// defaultVolumeReplicationClassAnnotation marks the VolumeReplicationClass
+	// chosen for the VolumeReplications of the driver that set none.
+	defaultVolumeReplicationClassAnnotation = replicationParameterPrefix + "is-default-class"
+	// storageClassLabel maps a VolumeReplicationClass to the StorageClass
+	// whose claims it is chosen for.
+	storageClassLabel = replicationParameterPrefix + "storageclass"
 )
 
 // getVolumeReplicationClass get volume replication class object from the subjected namespace and return the same.
@@ -45,3 +57,119 @@ func (r VolumeReplicationReconciler) getVolumeReplicationClass(ctx context.Conte
 
 	return vrcObj, nil
 }
+
+// isDriverClass returns true if the VolumeReplicationClass exists and belongs
+// to the driver.
+func isDriverClass(ctx context.Context, c client.Reader, driverName, vrcName string) (bool, error) {
+	vrcObj := &replicationv1alpha1.VolumeReplicationClass{}
+
+	err := c.Get(ctx, types.NamespacedName{Name: vrcName}, vrcObj)
+	if err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+
+	return vrcObj.Spec.Provisioner == driverName, nil
+}
+
+// getVolumeReplicationClassName returns the VolumeReplicationClass of the
+// instance. The class of the spec takes precedence over the one recorded in
+// the status, which is only chosen once so that the VolumeReplication keeps
+// its class when new ones are added. It returns false if the volume belongs
+// to another driver.
+func (r *VolumeReplicationReconciler) getVolumeReplicationClassName(
+	ctx context.Context,
+	logger logr.Logger,
+	instance *replicationv1alpha1.VolumeReplication,
+) (string, bool, error) {
+	if instance.Spec.VolumeReplicationClass != "" {
+		return instance.Spec.VolumeReplicationClass, true, nil
+	}
+
+	if instance.Status.VolumeReplicationClass != "" {
+		return instance.Status.VolumeReplicationClass, true, nil
+	}
+
+	if instance.Spec.DataSource.Kind != pvcDataSource {
+		return "", true, fmt.Errorf("volumeReplicationClass is required for dataSource of kind %q",
+			instance.Spec.DataSource.Kind)
+	}
+
+	pvc, pv, err := r.getPVCDataSource(ctx, logger,
+		types.NamespacedName{Name: instance.Spec.DataSource.Name, Namespace: instance.Namespace})
+	if err != nil {
+		return "", true, err
+	}
+
+	if pv.Spec.CSI == nil {
+		return "", true, fmt.Errorf("PV %q is not a CSI volume", pv.Name)
+	}
+
+	if pv.Spec.CSI.Driver != r.DriverConfig.DriverName {
+		return "", false, nil
+	}
+
+	vrcName, err := resolveVolumeReplicationClass(ctx, r, pv.Spec.CSI.Driver, pvc.Spec.StorageClassName)
+	if err != nil {
+		return "", true, err
+	}
+
+	logger.Info("chose volumeReplicationClass", "VRCName", vrcName)
+
+	return vrcName, true, nil
+}
+
+// resolveVolumeReplicationClass returns the VolumeReplicationClass of the
+// driver for a claim of the StorageClass. A class labeled with the
+// StorageClass takes precedence over the default class of the driver.
+func resolveVolumeReplicationClass(ctx context.Context, c client.Reader, driverName string, storageClassName *string) (string, error) {
+	vrcList := &replicationv1alpha1.VolumeReplicationClassList{}
+
+	err := c.List(ctx, vrcList)
+	if err != nil {
+		return "", err
+	}
+
+	var mapped, defaults []string
+
+	for i := range vrcList.Items {
+		vrc := &vrcList.Items[i]
+		if vrc.Spec.Provisioner != driverName {
+			continue
+		}
+
+		if storageClassName != nil && *storageClassName != "" && vrc.Labels[storageClassLabel] == *storageClassName {
+			mapped = append(mapped, vrc.Name)
+		}
+
+		if vrc.Annotations[defaultVolumeReplicationClassAnnotation] == "true" {
+			defaults = append(defaults, vrc.Name)
+		}
+	}
+
+	switch {
+	case len(mapped) == 1:
+		return mapped[0], nil
+	case len(mapped) > 1:
+		sort.Strings(mapped)
+
+		return "", fmt.Errorf("multiple volumeReplicationClasses %v are labeled with StorageClass %q", mapped, *storageClassName)
+	case len(defaults) == 1:
+		return defaults[0], nil
+	case len(defaults) > 1:
+		sort.Strings(defaults)
+
+		return "", fmt.Errorf("multiple default volumeReplicationClasses %v for driver %q", defaults, driverName)
+	}
+
+	return "", fmt.Errorf("no volumeReplicationClass found for driver %q", driverName)
+}
+
+// getRecordedClassName returns the VolumeReplicationClass of the spec, or the
+// one chosen and recorded in the status.
+func getRecordedClassName(vr *replicationv1alpha1.VolumeReplication) string {
+	if vr.Spec.VolumeReplicationClass != "" {
+		return vr.Spec.VolumeReplicationClass
+	}
+
+	return vr.Status.VolumeReplicationClass
+}
@@ -22,10 +22,14 @@ import (
 
 	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
 
+	"github.com/csi-addons/volume-replication-operator/pkg/client/fake"
 	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
 )
 
 var mockVolumeReplicationClassObj = &replicationv1alpha1.VolumeReplicationClass{
@@ -78,3 +82,147 @@ func TestGetVolumeReplicaClass(t *testing.T) {
 		}
 	}
 }
+
+func TestResolveVolumeReplicationClass(t *testing.T) {
+	t.Parallel()
+
+	class := func(name, provisioner string, labels, annotations map[string]string) runtime.Object {
+		return &replicationv1alpha1.VolumeReplicationClass{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels, Annotations: annotations},
+			Spec:       replicationv1alpha1.VolumeReplicationClassSpec{Provisioner: provisioner},
+		}
+	}
+	isDefault := map[string]string{defaultVolumeReplicationClassAnnotation: "true"}
+	fast := map[string]string{storageClassLabel: "fast"}
+
+	tests := []struct {
+		name         string
+		classes      []runtime.Object
+		storageClass *string
+		want         string
+		wantErr      bool
+	}{
+		{
+			name: "default class",
+			classes: []runtime.Object{
+				class("default", "test-driver", nil, isDefault),
+				class("other", "test-driver", nil, nil),
+			},
+			storageClass: ptr.To("fast"),
+			want:         "default",
+		},
+		{
+			name: "storage class label takes precedence",
+			classes: []runtime.Object{
+				class("default", "test-driver", nil, isDefault),
+				class("fast", "test-driver", fast, nil),
+			},
+			storageClass: ptr.To("fast"),
+			want:         "fast",
+		},
+		{
+			name: "classes of other drivers are ignored",
+			classes: []runtime.Object{
+				class("default", "test-driver", nil, isDefault),
+				class("fast", "other-driver", fast, isDefault),
+			},
+			storageClass: ptr.To("fast"),
+			want:         "default",
+		},
+		{
+			name: "multiple default classes",
+			classes: []runtime.Object{
+				class("default-a", "test-driver", nil, isDefault),
+				class("default-b", "test-driver", nil, isDefault),
+			},
+			wantErr: true,
+		},
+		{
+			name: "multiple labeled classes",
+			classes: []runtime.Object{
+				class("fast-a", "test-driver", fast, nil),
+				class("fast-b", "test-driver", fast, nil),
+			},
+			storageClass: ptr.To("fast"),
+			wantErr:      true,
+		},
+		{
+			name:    "no class",
+			classes: []runtime.Object{class("other", "test-driver", nil, nil)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			reconciler := createFakeVolumeReplicationReconciler(t, tt.classes...)
+
+			got, err := resolveVolumeReplicationClass(context.TODO(), reconciler, "test-driver", tt.storageClass)
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReconcileChoosesVolumeReplicationClass(t *testing.T) {
+	t.Parallel()
+
+	volumeReplication := mockVolumeReplicationObj.DeepCopy()
+	volumeReplication.Spec.VolumeReplicationClass = ""
+	volumeReplication.Spec.DataSource.Kind = pvcDataSource
+	volumeReplication.Spec.ReplicationState = replicationv1alpha1.Primary
+
+	defaultClass := mockVolumeReplicationClassObj.DeepCopy()
+	defaultClass.Annotations = map[string]string{defaultVolumeReplicationClassAnnotation: "true"}
+
+	pv := mockPersistentVolume.DeepCopy()
+	pv.Spec.CSI.Driver = "test-driver"
+
+	reconciler := createFakeVolumeReplicationReconciler(t,
+		volumeReplication,
+		defaultClass,
+		mockPersistentVolumeClaim.DeepCopy(),
+		pv,
+	)
+	reconciler.Replication = fake.NewStatefulReplicationClient(1)
+
+	ctx := context.TODO()
+	key := types.NamespacedName{Name: volumeReplication.Name, Namespace: volumeReplication.Namespace}
+
+	_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	latest := &replicationv1alpha1.VolumeReplication{}
+	require.NoError(t, reconciler.Get(ctx, key, latest))
+	require.Equal(t, defaultClass.Name, latest.Status.VolumeReplicationClass)
+	require.Equal(t, replicationv1alpha1.PrimaryState, latest.Status.State)
+
+	// the chosen class is kept once recorded
+	other := mockVolumeReplicationClassObj.DeepCopy()
+	other.Name = "other-default"
+	other.Annotations = defaultClass.Annotations
+	require.NoError(t, reconciler.Create(ctx, other))
+
+	vrcName, owned, err := reconciler.getVolumeReplicationClassName(ctx, reconciler.Log, latest)
+	require.NoError(t, err)
+	require.True(t, owned)
+	require.Equal(t, defaultClass.Name, vrcName)
+
+	// the volumes of other drivers are left to their operator
+	pv.Spec.CSI.Driver = "other-driver"
+	require.NoError(t, reconciler.Update(ctx, pv))
+
+	latest.Status.VolumeReplicationClass = ""
+
+	_, owned, err = reconciler.getVolumeReplicationClassName(ctx, reconciler.Log, latest)
+	require.NoError(t, err)
+	require.False(t, owned)
+}
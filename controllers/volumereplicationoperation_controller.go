@@ -0,0 +1,464 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// defaultOperationTimeout is the time a VolumeReplication has to complete
+	// its transition when the operation sets no timeout.
+	defaultOperationTimeout = 10 * time.Minute
+	// defaultOperationMaxConcurrent is the number of concurrent transitions
+	// when the operation sets no limit.
+	defaultOperationMaxConcurrent = 10
+	// operationPollInterval is the interval at which the transitions in
+	// progress are checked for their timeout.
+	operationPollInterval = 30 * time.Second
+)
+
+// volumeReplicationOperationReconciler applies the replication state of a
+// VolumeReplicationOperation to the selected VolumeReplications. The
+// transitions themselves are done by the VolumeReplication controller.
+type volumeReplicationOperationReconciler struct {
+	client.Client
+
+	log      logr.Logger
+	recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=replication.storage.openshift.io,resources=volumereplicationoperations,verbs=get;list;watch
+// +kubebuilder:rbac:groups=replication.storage.openshift.io,resources=volumereplicationoperations/status,verbs=get;update;patch
+
+// Reconcile starts the transitions of the selected VolumeReplications within
+// the limits of the operation and records their progress.
+func (r *volumeReplicationOperationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.log.WithValues("Request.Name", req.Name)
+
+	operation := &replicationv1alpha1.VolumeReplicationOperation{}
+
+	err := r.Get(ctx, req.NamespacedName, operation)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("volumeReplicationOperation resource not found")
+
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	if isOperationFinished(operation.Status.Phase) {
+		return ctrl.Result{}, nil
+	}
+
+	status := operation.Status.DeepCopy()
+
+	// the VolumeReplications are selected once, so that the operation is
+	// not extended by VolumeReplications created while it runs.
+	if status.StartTime == nil {
+		status.Targets, err = r.selectTargets(ctx, operation)
+		if err != nil {
+			logger.Error(err, "failed to select volumeReplications")
+
+			return ctrl.Result{}, err
+		}
+
+		status.StartTime = getCurrentTime()
+		logger.Info("selected volumeReplications", "Total", len(status.Targets))
+	}
+
+	r.progressTargets(ctx, logger, operation, status)
+
+	err = r.patchOperationStatus(ctx, operation, status)
+	if err != nil {
+		logger.Error(err, "failed to update volumeReplicationOperation status")
+
+		return ctrl.Result{}, err
+	}
+
+	switch status.Phase {
+	case replicationv1alpha1.OperationSucceeded:
+		r.recordEvent(operation, corev1.EventTypeNormal, "OperationSucceeded", status.Message)
+	case replicationv1alpha1.OperationFailed:
+		r.recordEvent(operation, corev1.EventTypeWarning, "OperationFailed", status.Message)
+	}
+
+	if status.InProgress > 0 {
+		// the VolumeReplication watch reports the completed transitions,
+		// polling is only needed for the timeouts.
+		return ctrl.Result{RequeueAfter: operationPollInterval}, nil
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// isOperationFinished returns true if no further transitions are started or
+// tracked for the operation.
+func isOperationFinished(phase replicationv1alpha1.OperationPhase) bool {
+	return phase == replicationv1alpha1.OperationSucceeded || phase == replicationv1alpha1.OperationFailed
+}
+
+// selectTargets returns the VolumeReplications selected by the operation.
+func (r *volumeReplicationOperationReconciler) selectTargets(
+	ctx context.Context,
+	operation *replicationv1alpha1.VolumeReplicationOperation,
+) ([]replicationv1alpha1.OperationTarget, error) {
+	selector := labels.Everything()
+
+	if operation.Spec.Selector != nil {
+		var err error
+
+		selector, err = metav1.LabelSelectorAsSelector(operation.Spec.Selector)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	namespaces := operation.Spec.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+
+	var targets []replicationv1alpha1.OperationTarget
+
+	for _, namespace := range namespaces {
+		vrList := &replicationv1alpha1.VolumeReplicationList{}
+
+		err := r.List(ctx, vrList, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector})
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range vrList.Items {
+			targets = append(targets, replicationv1alpha1.OperationTarget{
+				Namespace: vrList.Items[i].Namespace,
+				Name:      vrList.Items[i].Name,
+				Phase:     replicationv1alpha1.TargetPending,
+			})
+		}
+	}
+
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].Namespace != targets[j].Namespace {
+			return targets[i].Namespace < targets[j].Namespace
+		}
+
+		return targets[i].Name < targets[j].Name
+	})
+
+	return targets, nil
+}
+
+// progressTargets checks the transitions in progress, starts the pending ones
+// within the limits of the operation and updates the phase of the operation.
+func (r *volumeReplicationOperationReconciler) progressTargets(
+	ctx context.Context,
+	logger logr.Logger,
+	operation *replicationv1alpha1.VolumeReplicationOperation,
+	status *replicationv1alpha1.VolumeReplicationOperationStatus,
+) {
+	for i := range status.Targets {
+		if status.Targets[i].Phase == replicationv1alpha1.TargetInProgress {
+			r.checkTarget(ctx, operation, &status.Targets[i])
+		}
+	}
+
+	inProgress := countTargets(status, replicationv1alpha1.TargetInProgress)
+	failed := countTargets(status, replicationv1alpha1.TargetFailed)
+	maxConcurrent := int(operation.Spec.MaxConcurrent)
+	if maxConcurrent < 1 {
+		maxConcurrent = defaultOperationMaxConcurrent
+	}
+
+	for i := range status.Targets {
+		if operation.Spec.Suspend || inProgress >= maxConcurrent || isFailureThresholdExceeded(operation, failed) {
+			break
+		}
+
+		target := &status.Targets[i]
+		if target.Phase != replicationv1alpha1.TargetPending {
+			continue
+		}
+
+		r.startTarget(ctx, logger, operation, target)
+
+		switch target.Phase {
+		case replicationv1alpha1.TargetInProgress:
+			inProgress++
+		case replicationv1alpha1.TargetFailed:
+			failed++
+		}
+	}
+
+	pending := countTargets(status, replicationv1alpha1.TargetPending)
+	thresholdExceeded := isFailureThresholdExceeded(operation, failed)
+
+	status.Total = int32(len(status.Targets))
+	status.InProgress = int32(inProgress)
+	status.Succeeded = int32(countTargets(status, replicationv1alpha1.TargetSucceeded))
+	status.Failed = int32(failed)
+
+	switch {
+	case inProgress == 0 && thresholdExceeded:
+		status.Phase = replicationv1alpha1.OperationFailed
+		status.Message = fmt.Sprintf("%d of %d transitions failed, %d transitions were not started",
+			failed, len(status.Targets), pending)
+	case inProgress == 0 && pending == 0 && failed > 0:
+		status.Phase = replicationv1alpha1.OperationFailed
+		status.Message = fmt.Sprintf("%d of %d transitions failed", failed, len(status.Targets))
+	case inProgress == 0 && pending == 0:
+		status.Phase = replicationv1alpha1.OperationSucceeded
+		status.Message = fmt.Sprintf("%d transitions succeeded", len(status.Targets))
+	case operation.Spec.Suspend:
+		status.Phase = replicationv1alpha1.OperationSuspended
+		status.Message = fmt.Sprintf("suspended with %d transitions in progress and %d pending", inProgress, pending)
+	default:
+		status.Phase = replicationv1alpha1.OperationRunning
+		status.Message = fmt.Sprintf("%d transitions in progress and %d pending", inProgress, pending)
+	}
+
+	if isOperationFinished(status.Phase) {
+		status.CompletionTime = getCurrentTime()
+		logger.Info("volumeReplicationOperation finished", "Phase", status.Phase, "Message", status.Message)
+	}
+}
+
+// isFailureThresholdExceeded returns true if more transitions failed than
+// the operation tolerates.
+func isFailureThresholdExceeded(operation *replicationv1alpha1.VolumeReplicationOperation, failed int) bool {
+	return operation.Spec.MaxFailures != nil && failed > int(*operation.Spec.MaxFailures)
+}
+
+// countTargets returns the number of targets in the phase.
+func countTargets(status *replicationv1alpha1.VolumeReplicationOperationStatus, phase replicationv1alpha1.TargetPhase) int {
+	count := 0
+
+	for i := range status.Targets {
+		if status.Targets[i].Phase == phase {
+			count++
+		}
+	}
+
+	return count
+}
+
+// startTarget applies the replication state of the operation to the
+// VolumeReplication. The VolumeReplications with a controller are not
+// changed, their transition fails.
+func (r *volumeReplicationOperationReconciler) startTarget(
+	ctx context.Context,
+	logger logr.Logger,
+	operation *replicationv1alpha1.VolumeReplicationOperation,
+	target *replicationv1alpha1.OperationTarget,
+) {
+	target.StartTime = getCurrentTime()
+
+	vr := &replicationv1alpha1.VolumeReplication{}
+
+	err := r.Get(ctx, types.NamespacedName{Name: target.Name, Namespace: target.Namespace}, vr)
+	if err != nil {
+		failTarget(target, fmt.Sprintf("failed to get VolumeReplication: %v", err))
+
+		return
+	}
+
+	if vr.Spec.ReplicationState != operation.Spec.ReplicationState {
+		// the controller of the VolumeReplication, e.g. a
+		// VolumeReplicationPolicy, would revert the change.
+		if owner := metav1.GetControllerOf(vr); owner != nil {
+			failTarget(target, fmt.Sprintf("VolumeReplication is managed by %s %q, its replicationState must be changed there",
+				owner.Kind, owner.Name))
+
+			return
+		}
+
+		logger.Info("starting transition", "VRName", vr.Name, "Namespace", vr.Namespace,
+			"ReplicationState", operation.Spec.ReplicationState)
+
+		patch := client.MergeFrom(vr.DeepCopy())
+		vr.Spec.ReplicationState = operation.Spec.ReplicationState
+
+		err = r.Patch(ctx, vr, patch)
+		if err != nil {
+			failTarget(target, fmt.Sprintf("failed to update VolumeReplication: %v", err))
+
+			return
+		}
+	}
+
+	target.Phase = replicationv1alpha1.TargetInProgress
+
+	if isTransitionCompleted(vr, operation.Spec.ReplicationState) {
+		completeTarget(target)
+	}
+}
+
+// checkTarget completes or fails the transition in progress.
+func (r *volumeReplicationOperationReconciler) checkTarget(
+	ctx context.Context,
+	operation *replicationv1alpha1.VolumeReplicationOperation,
+	target *replicationv1alpha1.OperationTarget,
+) {
+	vr := &replicationv1alpha1.VolumeReplication{}
+
+	err := r.Get(ctx, types.NamespacedName{Name: target.Name, Namespace: target.Namespace}, vr)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			failTarget(target, "VolumeReplication was deleted")
+		}
+
+		return
+	}
+
+	timeout := defaultOperationTimeout
+	if operation.Spec.Timeout != nil {
+		timeout = operation.Spec.Timeout.Duration
+	}
+
+	switch {
+	case isTransitionCompleted(vr, operation.Spec.ReplicationState):
+		completeTarget(target)
+	case vr.Spec.ReplicationState != operation.Spec.ReplicationState:
+		failTarget(target, fmt.Sprintf("replicationState was changed to %q", vr.Spec.ReplicationState))
+	case target.StartTime != nil && time.Since(target.StartTime.Time) > timeout:
+		failTarget(target, fmt.Sprintf("transition timed out after %s: %s", timeout, vr.Status.Message))
+	}
+}
+
+// isTransitionCompleted returns true if the VolumeReplication completed the
+// transition to the replication state, including its post-promote hooks.
+func isTransitionCompleted(vr *replicationv1alpha1.VolumeReplication, state replicationv1alpha1.ReplicationState) bool {
+	expected := replicationv1alpha1.SecondaryState
+	if state == replicationv1alpha1.Primary {
+		expected = replicationv1alpha1.PrimaryState
+	}
+
+	if vr.Spec.ReplicationState != state || vr.Status.State != expected ||
+		isHookRunning(vr, replicationv1alpha1.PostPromoteHook) {
+		return false
+	}
+
+	completed := findCondition(vr.Status.Conditions, ConditionCompleted)
+
+	return completed != nil && completed.Status == metav1.ConditionTrue &&
+		completed.ObservedGeneration == vr.Generation
+}
+
+func completeTarget(target *replicationv1alpha1.OperationTarget) {
+	target.Phase = replicationv1alpha1.TargetSucceeded
+	target.Message = ""
+	target.CompletionTime = getCurrentTime()
+}
+
+func failTarget(target *replicationv1alpha1.OperationTarget, message string) {
+	target.Phase = replicationv1alpha1.TargetFailed
+	target.Message = message
+	target.CompletionTime = getCurrentTime()
+}
+
+// patchOperationStatus writes the status of the operation, it fails on
+// conflicts so that the operation is reconciled again from the latest state.
+func (r *volumeReplicationOperationReconciler) patchOperationStatus(
+	ctx context.Context,
+	operation *replicationv1alpha1.VolumeReplicationOperation,
+	status *replicationv1alpha1.VolumeReplicationOperationStatus,
+) error {
+	patched := operation.DeepCopy()
+	patched.Status = *status
+
+	return r.Status().Patch(ctx, patched,
+		client.MergeFromWithOptions(operation, client.MergeFromWithOptimisticLock{}),
+		client.FieldOwner(statusFieldManager))
+}
+
+func (r *volumeReplicationOperationReconciler) recordEvent(obj client.Object, eventType, reason, message string) {
+	if r.recorder == nil {
+		return
+	}
+
+	r.recorder.Event(obj, eventType, reason, message)
+}
+
+// volumeReplicationOperationsForVR returns the running operations tracking
+// the transition of the VolumeReplication.
+func (r *volumeReplicationOperationReconciler) volumeReplicationOperationsForVR(
+	ctx context.Context,
+	obj client.Object,
+) []reconcile.Request {
+	operations := &replicationv1alpha1.VolumeReplicationOperationList{}
+
+	err := r.List(ctx, operations)
+	if err != nil {
+		r.log.Error(err, "failed to list volumeReplicationOperations")
+
+		return nil
+	}
+
+	var requests []reconcile.Request
+
+	for i := range operations.Items {
+		operation := &operations.Items[i]
+		if isOperationFinished(operation.Status.Phase) {
+			continue
+		}
+
+		for _, target := range operation.Status.Targets {
+			if target.Phase == replicationv1alpha1.TargetInProgress &&
+				target.Name == obj.GetName() && target.Namespace == obj.GetNamespace() {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{Name: operation.Name},
+				})
+
+				break
+			}
+		}
+	}
+
+	return requests
+}
+
+// setupWithManager builds the VolumeReplicationOperation controller.
+func (r *volumeReplicationOperationReconciler) setupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		// the status writes of the operation do not trigger a reconcile
+		For(&replicationv1alpha1.VolumeReplicationOperation{},
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&replicationv1alpha1.VolumeReplication{},
+			handler.EnqueueRequestsFromMapFunc(r.volumeReplicationOperationsForVR)).
+		Complete(r)
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// mockOperationTarget returns a secondary VolumeReplication with the labels.
+func mockOperationTarget(namespace, name string, labels map[string]string) *replicationv1alpha1.VolumeReplication {
+	return &replicationv1alpha1.VolumeReplication{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+		Spec: replicationv1alpha1.VolumeReplicationSpec{
+			ReplicationState: replicationv1alpha1.Secondary,
+		},
+		Status: replicationv1alpha1.VolumeReplicationStatus{
+			State: replicationv1alpha1.SecondaryState,
+		},
+	}
+}
+
+func createFakeOperationReconciler(t *testing.T, obj ...runtime.Object) *volumeReplicationOperationReconciler {
+	t.Helper()
+
+	return &volumeReplicationOperationReconciler{
+		Client: createFakeVolumeReplicationReconciler(t, obj...).Client,
+		log:    logf.Log.WithName("controller_volumereplicationoperation_test"),
+	}
+}
+
+func TestVolumeReplicationOperation(t *testing.T) {
+	t.Parallel()
+
+	selected := map[string]string{"app": "db"}
+	operation := &replicationv1alpha1.VolumeReplicationOperation{
+		ObjectMeta: metav1.ObjectMeta{Name: "failover"},
+		Spec: replicationv1alpha1.VolumeReplicationOperationSpec{
+			Namespaces:       []string{mockNamespace},
+			Selector:         &metav1.LabelSelector{MatchLabels: selected},
+			ReplicationState: replicationv1alpha1.Primary,
+			MaxConcurrent:    2,
+		},
+	}
+
+	reconciler := createFakeOperationReconciler(t,
+		operation,
+		mockOperationTarget(mockNamespace, "vr-a", selected),
+		mockOperationTarget(mockNamespace, "vr-b", selected),
+		mockOperationTarget(mockNamespace, "vr-c", selected),
+		mockOperationTarget(mockNamespace, "unselected", nil),
+		mockOperationTarget("other-ns", "vr-a", selected),
+	)
+
+	ctx := context.TODO()
+	key := types.NamespacedName{Name: operation.Name}
+
+	getVR := func(name string) *replicationv1alpha1.VolumeReplication {
+		vr := &replicationv1alpha1.VolumeReplication{}
+		require.NoError(t, reconciler.Get(ctx, types.NamespacedName{Name: name, Namespace: mockNamespace}, vr))
+
+		return vr
+	}
+
+	promote := func(name string) {
+		vr := getVR(name)
+		vr.Status.State = replicationv1alpha1.PrimaryState
+		setPromotedCondition(&vr.Status.Conditions, vr.Generation)
+		require.NoError(t, reconciler.Status().Update(ctx, vr))
+	}
+
+	getPhases := func() map[string]replicationv1alpha1.TargetPhase {
+		require.NoError(t, reconciler.Get(ctx, key, operation))
+
+		phases := map[string]replicationv1alpha1.TargetPhase{}
+		for _, target := range operation.Status.Targets {
+			require.Equal(t, mockNamespace, target.Namespace)
+			phases[target.Name] = target.Phase
+		}
+
+		return phases
+	}
+
+	// the transitions are started within the concurrency limit
+	result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	require.Equal(t, ctrl.Result{RequeueAfter: operationPollInterval}, result)
+
+	require.Equal(t, map[string]replicationv1alpha1.TargetPhase{
+		"vr-a": replicationv1alpha1.TargetInProgress,
+		"vr-b": replicationv1alpha1.TargetInProgress,
+		"vr-c": replicationv1alpha1.TargetPending,
+	}, getPhases())
+	require.Equal(t, replicationv1alpha1.OperationRunning, operation.Status.Phase)
+	require.Equal(t, int32(3), operation.Status.Total)
+	require.Equal(t, replicationv1alpha1.Primary, getVR("vr-a").Spec.ReplicationState)
+	require.Equal(t, replicationv1alpha1.Secondary, getVR("vr-c").Spec.ReplicationState)
+
+	// completed transitions free their slot, a changed replication state
+	// fails the transition
+	promote("vr-a")
+
+	vrB := getVR("vr-b")
+	vrB.Spec.ReplicationState = replicationv1alpha1.Secondary
+	require.NoError(t, reconciler.Update(ctx, vrB))
+
+	require.Equal(t, []reconcile.Request{{NamespacedName: key}},
+		reconciler.volumeReplicationOperationsForVR(ctx, getVR("vr-a")))
+	require.Empty(t, reconciler.volumeReplicationOperationsForVR(ctx, getVR("unselected")))
+
+	_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]replicationv1alpha1.TargetPhase{
+		"vr-a": replicationv1alpha1.TargetSucceeded,
+		"vr-b": replicationv1alpha1.TargetFailed,
+		"vr-c": replicationv1alpha1.TargetInProgress,
+	}, getPhases())
+	require.Contains(t, operation.Status.Targets[1].Message, "replicationState was changed")
+
+	promote("vr-c")
+
+	result, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	require.Equal(t, ctrl.Result{}, result)
+
+	require.NoError(t, reconciler.Get(ctx, key, operation))
+	require.Equal(t, replicationv1alpha1.OperationFailed, operation.Status.Phase)
+	require.Equal(t, int32(2), operation.Status.Succeeded)
+	require.Equal(t, int32(1), operation.Status.Failed)
+	require.NotNil(t, operation.Status.CompletionTime)
+}
+
+func TestVolumeReplicationOperationSuspendAndFailureThreshold(t *testing.T) {
+	t.Parallel()
+
+	operation := &replicationv1alpha1.VolumeReplicationOperation{
+		ObjectMeta: metav1.ObjectMeta{Name: "failover"},
+		Spec: replicationv1alpha1.VolumeReplicationOperationSpec{
+			ReplicationState: replicationv1alpha1.Primary,
+			MaxConcurrent:    1,
+			MaxFailures:      ptr.To[int32](0),
+			Suspend:          true,
+		},
+	}
+
+	reconciler := createFakeOperationReconciler(t,
+		operation,
+		mockOperationTarget(mockNamespace, "vr-a", nil),
+		mockOperationTarget(mockNamespace, "vr-b", nil),
+	)
+
+	ctx := context.TODO()
+	key := types.NamespacedName{Name: operation.Name}
+
+	// no transitions are started while suspended
+	_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	require.NoError(t, reconciler.Get(ctx, key, operation))
+	require.Equal(t, replicationv1alpha1.OperationSuspended, operation.Status.Phase)
+	require.Equal(t, int32(2), operation.Status.Total)
+	require.Equal(t, int32(0), operation.Status.InProgress)
+
+	// a transition failing after the resume stops the operation
+	require.NoError(t, reconciler.Delete(ctx, mockOperationTarget(mockNamespace, "vr-a", nil)))
+
+	operation.Spec.Suspend = false
+	require.NoError(t, reconciler.Update(ctx, operation))
+
+	_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	require.NoError(t, reconciler.Get(ctx, key, operation))
+	require.Equal(t, replicationv1alpha1.OperationFailed, operation.Status.Phase)
+	require.Equal(t, replicationv1alpha1.TargetFailed, operation.Status.Targets[0].Phase)
+	require.Equal(t, replicationv1alpha1.TargetPending, operation.Status.Targets[1].Phase)
+	require.Equal(t, "1 of 2 transitions failed, 1 transitions were not started", operation.Status.Message)
+
+	vr := &replicationv1alpha1.VolumeReplication{}
+	require.NoError(t, reconciler.Get(ctx, types.NamespacedName{Name: "vr-b", Namespace: mockNamespace}, vr))
+	require.Equal(t, replicationv1alpha1.Secondary, vr.Spec.ReplicationState)
+}
+
+func TestVolumeReplicationOperationSkipsControlledTargets(t *testing.T) {
+	t.Parallel()
+
+	operation := &replicationv1alpha1.VolumeReplicationOperation{
+		ObjectMeta: metav1.ObjectMeta{Name: "failover"},
+		Spec: replicationv1alpha1.VolumeReplicationOperationSpec{
+			ReplicationState: replicationv1alpha1.Primary,
+		},
+	}
+
+	controlled := mockOperationTarget(mockNamespace, "vr-a", nil)
+	controlled.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: replicationv1alpha1.GroupVersion.String(),
+		Kind:       "VolumeReplicationPolicy",
+		Name:       "db-policy",
+		UID:        "policy-uid",
+		Controller: ptr.To(true),
+	}}
+
+	reconciler := createFakeOperationReconciler(t, operation, controlled)
+
+	ctx := context.TODO()
+	key := types.NamespacedName{Name: operation.Name}
+
+	_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	require.NoError(t, reconciler.Get(ctx, key, operation))
+	require.Equal(t, replicationv1alpha1.OperationFailed, operation.Status.Phase)
+	require.Equal(t, replicationv1alpha1.TargetFailed, operation.Status.Targets[0].Phase)
+	require.Contains(t, operation.Status.Targets[0].Message, `VolumeReplicationPolicy "db-policy"`)
+
+	vr := &replicationv1alpha1.VolumeReplication{}
+	require.NoError(t, reconciler.Get(ctx, client.ObjectKeyFromObject(controlled), vr))
+	require.Equal(t, replicationv1alpha1.Secondary, vr.Spec.ReplicationState)
+}
@@ -0,0 +1,347 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// volumeReplicationPolicyReconciler keeps a VolumeReplication for each claim
+// selected by a VolumeReplicationPolicy. It only handles the policies using a
+// class of the driver of the operator.
+type volumeReplicationPolicyReconciler struct {
+	client.Client
+
+	scheme     *runtime.Scheme
+	log        logr.Logger
+	recorder   record.EventRecorder
+	driverName string
+}
+
+// +kubebuilder:rbac:groups=replication.storage.openshift.io,resources=volumereplicationpolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups=replication.storage.openshift.io,resources=volumereplicationpolicies/status,verbs=get;update;patch
+
+// Reconcile creates, updates and deletes the VolumeReplications of the policy
+// and aggregates their status.
+func (r *volumeReplicationPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.log.WithValues("Request.Name", req.Name, "Request.Namespace", req.Namespace)
+
+	policy := &replicationv1alpha1.VolumeReplicationPolicy{}
+
+	err := r.Get(ctx, req.NamespacedName, policy)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("volumeReplicationPolicy resource not found")
+
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	// the VolumeReplications of a deleted policy are garbage collected
+	if !policy.GetDeletionTimestamp().IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	owned, err := isDriverClass(ctx, r, r.driverName, policy.Spec.VolumeReplicationClass)
+	if err != nil || !owned {
+		return ctrl.Result{}, err
+	}
+
+	pvcs, err := r.getSelectedClaims(ctx, policy)
+	if err != nil {
+		logger.Error(err, "failed to list the claims of the policy")
+
+		return ctrl.Result{}, err
+	}
+
+	vrList := &replicationv1alpha1.VolumeReplicationList{}
+
+	err = r.List(ctx, vrList, client.InNamespace(policy.Namespace))
+	if err != nil {
+		logger.Error(err, "failed to list volumeReplications")
+
+		return ctrl.Result{}, err
+	}
+
+	vrs := map[string]*replicationv1alpha1.VolumeReplication{}
+	for i := range vrList.Items {
+		vrs[vrList.Items[i].Name] = &vrList.Items[i]
+	}
+
+	members := make([]replicationv1alpha1.PolicyMember, 0, len(pvcs))
+	selected := map[string]bool{}
+
+	for _, pvc := range pvcs {
+		selected[pvc.Name] = true
+
+		member, err := r.syncMember(ctx, logger, policy, pvc, vrs[pvc.Name])
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		members = append(members, member)
+	}
+
+	// the VolumeReplications of claims that are no longer selected, or are
+	// being deleted, are deleted
+	for _, vr := range vrs {
+		if selected[vr.Name] || !metav1.IsControlledBy(vr, policy) || !vr.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+
+		logger.Info("deleting volumeReplication of unselected claim", "VRName", vr.Name)
+
+		err = r.Delete(ctx, vr)
+		if err != nil && !apierrors.IsNotFound(err) {
+			logger.Error(err, "failed to delete volumeReplication", "VRName", vr.Name)
+
+			return ctrl.Result{}, err
+		}
+
+		r.recordEvent(policy, corev1.EventTypeNormal, "VolumeReplicationDeleted",
+			fmt.Sprintf("deleted VolumeReplication of claim %q that is no longer selected", vr.Name))
+	}
+
+	err = r.updatePolicyStatus(ctx, policy, members)
+	if err != nil {
+		logger.Error(err, "failed to update volumeReplicationPolicy status")
+
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// getSelectedClaims returns the claims selected by the policy, sorted by name.
+func (r *volumeReplicationPolicyReconciler) getSelectedClaims(
+	ctx context.Context,
+	policy *replicationv1alpha1.VolumeReplicationPolicy,
+) ([]*corev1.PersistentVolumeClaim, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&policy.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+
+	pvcList := &corev1.PersistentVolumeClaimList{}
+
+	err = r.List(ctx, pvcList, client.InNamespace(policy.Namespace), client.MatchingLabelsSelector{Selector: selector})
+	if err != nil {
+		return nil, err
+	}
+
+	pvcs := make([]*corev1.PersistentVolumeClaim, 0, len(pvcList.Items))
+
+	for i := range pvcList.Items {
+		if pvcList.Items[i].GetDeletionTimestamp().IsZero() {
+			pvcs = append(pvcs, &pvcList.Items[i])
+		}
+	}
+
+	sort.Slice(pvcs, func(i, j int) bool {
+		return pvcs[i].Name < pvcs[j].Name
+	})
+
+	return pvcs, nil
+}
+
+// syncMember creates or updates the VolumeReplication of the claim and
+// returns the member reporting it.
+func (r *volumeReplicationPolicyReconciler) syncMember(
+	ctx context.Context,
+	logger logr.Logger,
+	policy *replicationv1alpha1.VolumeReplicationPolicy,
+	pvc *corev1.PersistentVolumeClaim,
+	vr *replicationv1alpha1.VolumeReplication,
+) (replicationv1alpha1.PolicyMember, error) {
+	member := replicationv1alpha1.PolicyMember{PVCName: pvc.Name}
+
+	if vr == nil {
+		vr = &replicationv1alpha1.VolumeReplication{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      pvc.Name,
+				Namespace: pvc.Namespace,
+			},
+		}
+		applyPolicySpec(policy, vr)
+
+		err := controllerutil.SetControllerReference(policy, vr, r.scheme)
+		if err != nil {
+			return member, err
+		}
+
+		logger.Info("creating volumeReplication for claim", "PVCName", pvc.Name)
+
+		err = r.Create(ctx, vr)
+		if err != nil {
+			logger.Error(err, "failed to create volumeReplication", "PVCName", pvc.Name)
+
+			return member, err
+		}
+
+		r.recordEvent(policy, corev1.EventTypeNormal, "VolumeReplicationCreated",
+			fmt.Sprintf("created VolumeReplication for claim %q", pvc.Name))
+
+		member.Message = "VolumeReplication created"
+
+		return member, nil
+	}
+
+	if !metav1.IsControlledBy(vr, policy) {
+		member.Message = fmt.Sprintf("VolumeReplication %q is not owned by the policy", vr.Name)
+
+		return member, nil
+	}
+
+	// changes of the policy fan out to all its VolumeReplications
+	patch := client.MergeFrom(vr.DeepCopy())
+	if applyPolicySpec(policy, vr) {
+		logger.Info("updating volumeReplication of claim", "VRName", vr.Name,
+			"ReplicationState", policy.Spec.ReplicationState)
+
+		err := r.Patch(ctx, vr, patch)
+		if err != nil {
+			logger.Error(err, "failed to update volumeReplication", "VRName", vr.Name)
+
+			return member, err
+		}
+	}
+
+	member.State = vr.Status.State
+	member.Completed = isTransitionCompleted(vr, policy.Spec.ReplicationState)
+
+	if !member.Completed {
+		member.Message = vr.Status.Message
+	}
+
+	return member, nil
+}
+
+// applyPolicySpec sets the spec of the VolumeReplication from the policy and
+// returns true if it changed.
+func applyPolicySpec(policy *replicationv1alpha1.VolumeReplicationPolicy, vr *replicationv1alpha1.VolumeReplication) bool {
+	spec := vr.Spec
+	spec.VolumeReplicationClass = policy.Spec.VolumeReplicationClass
+	spec.ReplicationState = policy.Spec.ReplicationState
+	spec.AutoResync = policy.Spec.AutoResync
+	spec.DataSource = corev1.TypedLocalObjectReference{Kind: pvcDataSource, Name: vr.Name}
+
+	changed := spec.VolumeReplicationClass != vr.Spec.VolumeReplicationClass ||
+		spec.ReplicationState != vr.Spec.ReplicationState ||
+		spec.AutoResync != vr.Spec.AutoResync ||
+		spec.DataSource != vr.Spec.DataSource
+	vr.Spec = spec
+
+	return changed
+}
+
+// updatePolicyStatus writes the members of the policy and the aggregated
+// condition.
+func (r *volumeReplicationPolicyReconciler) updatePolicyStatus(
+	ctx context.Context,
+	policy *replicationv1alpha1.VolumeReplicationPolicy,
+	members []replicationv1alpha1.PolicyMember,
+) error {
+	patched := policy.DeepCopy()
+
+	completed := 0
+
+	for _, member := range members {
+		if member.Completed {
+			completed++
+		}
+	}
+
+	patched.Status.ObservedGeneration = policy.Generation
+	patched.Status.Total = int32(len(members))
+	patched.Status.Completed = int32(completed)
+	patched.Status.Members = members
+	setPolicyCompletedCondition(&patched.Status.Conditions, policy.Generation, completed, len(members))
+
+	return r.Status().Patch(ctx, patched,
+		client.MergeFromWithOptions(policy, client.MergeFromWithOptimisticLock{}),
+		client.FieldOwner(statusFieldManager))
+}
+
+func (r *volumeReplicationPolicyReconciler) recordEvent(obj client.Object, eventType, reason, message string) {
+	if r.recorder == nil {
+		return
+	}
+
+	r.recorder.Event(obj, eventType, reason, message)
+}
+
+// volumeReplicationPoliciesForClaim returns the policies of the namespace of
+// the claim. All of them are returned, as a claim whose labels changed might
+// no longer be selected by a policy.
+func (r *volumeReplicationPolicyReconciler) volumeReplicationPoliciesForClaim(
+	ctx context.Context,
+	obj client.Object,
+) []reconcile.Request {
+	policies := &replicationv1alpha1.VolumeReplicationPolicyList{}
+
+	err := r.List(ctx, policies, client.InNamespace(obj.GetNamespace()))
+	if err != nil {
+		r.log.Error(err, "failed to list volumeReplicationPolicies", "Namespace", obj.GetNamespace())
+
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(policies.Items))
+	for i := range policies.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: policies.Items[i].Name, Namespace: policies.Items[i].Namespace},
+		})
+	}
+
+	return requests
+}
+
+// setupWithManager builds the VolumeReplicationPolicy controller.
+func (r *volumeReplicationPolicyReconciler) setupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&replicationv1alpha1.VolumeReplicationPolicy{},
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// the status of the VolumeReplications is aggregated by the policy
+		Owns(&replicationv1alpha1.VolumeReplication{}).
+		Watches(&corev1.PersistentVolumeClaim{},
+			handler.EnqueueRequestsFromMapFunc(r.volumeReplicationPoliciesForClaim),
+			// a deleted claim is no longer selected, its VolumeReplication
+			// protects it with a finalizer until it is deleted
+			builder.WithPredicates(predicate.Or[client.Object](predicate.LabelChangedPredicate{}, deletionPredicate()))).
+		Complete(r)
+}
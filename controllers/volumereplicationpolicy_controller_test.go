@@ -0,0 +1,166 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestVolumeReplicationPolicy(t *testing.T) {
+	t.Parallel()
+
+	selected := map[string]string{"app": "db"}
+	policy := &replicationv1alpha1.VolumeReplicationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: mockNamespace, UID: "policy-uid"},
+		Spec: replicationv1alpha1.VolumeReplicationPolicySpec{
+			Selector:               metav1.LabelSelector{MatchLabels: selected},
+			VolumeReplicationClass: mockVolumeReplicationClassObj.Name,
+			ReplicationState:       replicationv1alpha1.Primary,
+		},
+	}
+
+	claim := func(name string, labels map[string]string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: mockNamespace, Labels: labels},
+		}
+	}
+
+	unowned := mockVolumeReplicationObj.DeepCopy()
+	unowned.Name = "db-2"
+	unowned.Spec.ReplicationState = replicationv1alpha1.Primary
+
+	fakeReconciler := createFakeVolumeReplicationReconciler(t,
+		policy,
+		mockVolumeReplicationClassObj.DeepCopy(),
+		claim("db-0", selected),
+		claim("db-1", selected),
+		claim("db-2", selected),
+		claim("unselected", nil),
+		unowned,
+	)
+	reconciler := &volumeReplicationPolicyReconciler{
+		Client:     fakeReconciler.Client,
+		scheme:     fakeReconciler.Scheme,
+		log:        logf.Log.WithName("controller_volumereplicationpolicy_test"),
+		driverName: fakeReconciler.DriverConfig.DriverName,
+	}
+
+	ctx := context.TODO()
+	key := types.NamespacedName{Name: policy.Name, Namespace: policy.Namespace}
+
+	getVR := func(name string) *replicationv1alpha1.VolumeReplication {
+		vr := &replicationv1alpha1.VolumeReplication{}
+		require.NoError(t, reconciler.Get(ctx, types.NamespacedName{Name: name, Namespace: mockNamespace}, vr))
+
+		return vr
+	}
+
+	reconcilePolicy := func() {
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		require.NoError(t, err)
+		require.NoError(t, reconciler.Get(ctx, key, policy))
+	}
+
+	// a VolumeReplication is created for each selected claim
+	reconcilePolicy()
+
+	for _, name := range []string{"db-0", "db-1"} {
+		vr := getVR(name)
+		require.True(t, metav1.IsControlledBy(vr, policy))
+		require.Equal(t, replicationv1alpha1.Primary, vr.Spec.ReplicationState)
+		require.Equal(t, mockVolumeReplicationClassObj.Name, vr.Spec.VolumeReplicationClass)
+		require.Equal(t, name, vr.Spec.DataSource.Name)
+	}
+
+	require.Empty(t, getVR("db-2").OwnerReferences)
+	require.True(t, apierrors.IsNotFound(reconciler.Get(ctx, types.NamespacedName{Name: "unselected", Namespace: mockNamespace},
+		&replicationv1alpha1.VolumeReplication{})))
+
+	require.Equal(t, int32(3), policy.Status.Total)
+	require.Equal(t, int32(0), policy.Status.Completed)
+	require.Contains(t, policy.Status.Members[2].Message, "not owned by the policy")
+
+	completed := findCondition(policy.Status.Conditions, ConditionCompleted)
+	require.NotNil(t, completed)
+	require.Equal(t, MembersPending, completed.Reason)
+
+	// the status of the members is aggregated
+	vr := getVR("db-0")
+	vr.Status.State = replicationv1alpha1.PrimaryState
+	setPromotedCondition(&vr.Status.Conditions, vr.Generation)
+	require.NoError(t, reconciler.Status().Update(ctx, vr))
+
+	reconcilePolicy()
+	require.Equal(t, int32(1), policy.Status.Completed)
+	require.True(t, policy.Status.Members[0].Completed)
+	require.Equal(t, replicationv1alpha1.PrimaryState, policy.Status.Members[0].State)
+
+	// the state of the policy fans out to all its VolumeReplications
+	policy.Spec.ReplicationState = replicationv1alpha1.Secondary
+	policy.Spec.AutoResync = true
+	require.NoError(t, reconciler.Update(ctx, policy))
+
+	reconcilePolicy()
+
+	for _, name := range []string{"db-0", "db-1"} {
+		require.Equal(t, replicationv1alpha1.Secondary, getVR(name).Spec.ReplicationState)
+		require.True(t, getVR(name).Spec.AutoResync)
+	}
+
+	require.Equal(t, replicationv1alpha1.Primary, getVR("db-2").Spec.ReplicationState)
+	require.Equal(t, int32(0), policy.Status.Completed)
+
+	// the VolumeReplication of a claim no longer selected is deleted
+	pvc := &corev1.PersistentVolumeClaim{}
+	require.NoError(t, reconciler.Get(ctx, types.NamespacedName{Name: "db-1", Namespace: mockNamespace}, pvc))
+	require.Equal(t, []reconcile.Request{{NamespacedName: key}}, reconciler.volumeReplicationPoliciesForClaim(ctx, pvc))
+
+	pvc.Labels = nil
+	require.NoError(t, reconciler.Update(ctx, pvc))
+
+	reconcilePolicy()
+	require.True(t, apierrors.IsNotFound(reconciler.Get(ctx, types.NamespacedName{Name: "db-1", Namespace: mockNamespace},
+		&replicationv1alpha1.VolumeReplication{})))
+	require.Equal(t, int32(2), policy.Status.Total)
+
+	// and so is the VolumeReplication of a deleted claim
+	require.NoError(t, reconciler.Get(ctx, types.NamespacedName{Name: "db-0", Namespace: mockNamespace}, pvc))
+	oldPVC := pvc.DeepCopy()
+	pvc.Finalizers = []string{pvcReplicationFinalizer}
+	require.NoError(t, reconciler.Update(ctx, pvc))
+	require.NoError(t, reconciler.Delete(ctx, pvc))
+	require.NoError(t, reconciler.Get(ctx, types.NamespacedName{Name: "db-0", Namespace: mockNamespace}, pvc))
+	require.True(t, deletionPredicate().Update(event.UpdateEvent{ObjectOld: oldPVC, ObjectNew: pvc}))
+
+	reconcilePolicy()
+	require.True(t, apierrors.IsNotFound(reconciler.Get(ctx, types.NamespacedName{Name: "db-0", Namespace: mockNamespace},
+		&replicationv1alpha1.VolumeReplication{})))
+	require.Equal(t, int32(1), policy.Status.Total)
+}
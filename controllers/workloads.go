@@ -0,0 +1,205 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// standbyReplicasAnnotation marks a workload as a DR standby, it is
+	// scaled up from zero to the given number of replicas after a promotion.
+	standbyReplicasAnnotation = replicationParameterPrefix + "standby-replicas"
+	// restartedAtAnnotation is set on the pod template to restart a
+	// workload, as done by "kubectl rollout restart".
+	restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+)
+
+// podSpecUsesClaims returns true if the pod spec mounts any of the claims.
+func podSpecUsesClaims(spec *corev1.PodSpec, pvcNames []string) bool {
+	for _, volume := range spec.Volumes {
+		if volume.PersistentVolumeClaim != nil && slices.Contains(pvcNames, volume.PersistentVolumeClaim.ClaimName) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// statefulSetUsesClaims returns true if the StatefulSet mounts any of the
+// claims, either directly or through its volume claim templates.
+func statefulSetUsesClaims(sts *appsv1.StatefulSet, pvcNames []string) bool {
+	if podSpecUsesClaims(&sts.Spec.Template.Spec, pvcNames) {
+		return true
+	}
+
+	for _, template := range sts.Spec.VolumeClaimTemplates {
+		prefix := template.Name + "-" + sts.Name + "-"
+
+		for _, pvcName := range pvcNames {
+			ordinal, found := strings.CutPrefix(pvcName, prefix)
+			if !found {
+				continue
+			}
+
+			if _, err := strconv.Atoi(ordinal); err == nil {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// getStandbyReplicas returns the number of replicas a DR standby workload is
+// scaled up to, and false if the workload is not a standby or is already
+// scaled up.
+func getStandbyReplicas(obj client.Object, replicas *int32) (int32, bool, error) {
+	value, ok := obj.GetAnnotations()[standbyReplicasAnnotation]
+	if !ok || (replicas != nil && *replicas != 0) {
+		return 0, false, nil
+	}
+
+	standby, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || standby < 1 {
+		return 0, false, fmt.Errorf("invalid %s annotation %q", standbyReplicasAnnotation, value)
+	}
+
+	return int32(standby), true, nil
+}
+
+// restartWorkloads restarts the Deployments and StatefulSets using the
+// replicated claims, and scales up the DR standbys among them. A failure is
+// recorded for the workload, it is not retried.
+func (r *VolumeReplicationReconciler) restartWorkloads(
+	ctx context.Context,
+	logger logr.Logger,
+	instance *replicationv1alpha1.VolumeReplication,
+) ([]replicationv1alpha1.TouchedWorkload, error) {
+	pvcNames := getSourcePVCNames(instance)
+	restartedAt := getCurrentTime().Format(time.RFC3339)
+
+	var touched []replicationv1alpha1.TouchedWorkload
+
+	deployments := &appsv1.DeploymentList{}
+
+	err := r.List(ctx, deployments, client.InNamespace(instance.Namespace))
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+		if !podSpecUsesClaims(&deployment.Spec.Template.Spec, pvcNames) {
+			continue
+		}
+
+		touched = append(touched, r.touchWorkload(ctx, logger, "Deployment", deployment,
+			&deployment.Spec.Replicas, &deployment.Spec.Template, restartedAt))
+	}
+
+	statefulSets := &appsv1.StatefulSetList{}
+
+	err = r.List(ctx, statefulSets, client.InNamespace(instance.Namespace))
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range statefulSets.Items {
+		sts := &statefulSets.Items[i]
+		if !statefulSetUsesClaims(sts, pvcNames) {
+			continue
+		}
+
+		touched = append(touched, r.touchWorkload(ctx, logger, "StatefulSet", sts,
+			&sts.Spec.Replicas, &sts.Spec.Template, restartedAt))
+	}
+
+	return touched, nil
+}
+
+// touchWorkload scales up the workload if it is a DR standby, and restarts it
+// otherwise.
+func (r *VolumeReplicationReconciler) touchWorkload(
+	ctx context.Context,
+	logger logr.Logger,
+	kind string,
+	obj client.Object,
+	replicas **int32,
+	template *corev1.PodTemplateSpec,
+	restartedAt string,
+) replicationv1alpha1.TouchedWorkload {
+	touched := replicationv1alpha1.TouchedWorkload{Kind: kind, Name: obj.GetName()}
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+
+	standby, scaleUp, err := getStandbyReplicas(obj, *replicas)
+	switch {
+	case err != nil:
+		touched.Action = replicationv1alpha1.WorkloadFailed
+		touched.Message = err.Error()
+
+		return touched
+	case scaleUp:
+		*replicas = &standby
+		touched.Action = replicationv1alpha1.WorkloadScaledUp
+		touched.Message = fmt.Sprintf("scaled up to %d replicas", standby)
+	default:
+		if template.Annotations == nil {
+			template.Annotations = map[string]string{}
+		}
+
+		template.Annotations[restartedAtAnnotation] = restartedAt
+		touched.Action = replicationv1alpha1.WorkloadRestarted
+	}
+
+	logger.Info("touching workload after promotion", "Kind", kind, "Name", obj.GetName(), "Action", touched.Action)
+
+	err = r.Patch(ctx, obj, patch)
+	if err != nil {
+		logger.Error(err, "failed to touch workload after promotion", "Kind", kind, "Name", obj.GetName())
+		touched.Action = replicationv1alpha1.WorkloadFailed
+		touched.Message = err.Error()
+	}
+
+	return touched
+}
+
+// workloadRestartEventMessage summarizes the touched workloads for an event.
+func workloadRestartEventMessage(touched []replicationv1alpha1.TouchedWorkload) string {
+	if len(touched) == 0 {
+		return "no workload uses the volume"
+	}
+
+	items := make([]string, 0, len(touched))
+	for _, workload := range touched {
+		items = append(items, fmt.Sprintf("%s/%s %s", strings.ToLower(workload.Kind), workload.Name, workload.Action))
+	}
+
+	return "touched workloads after promotion: " + strings.Join(items, ", ")
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	replicationv1alpha1 "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
+	"github.com/csi-addons/volume-replication-operator/pkg/client/fake"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestStatefulSetUsesClaims(t *testing.T) {
+	t.Parallel()
+
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db"},
+		Spec: appsv1.StatefulSetSpec{
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{
+				ObjectMeta: metav1.ObjectMeta{Name: "data"},
+			}},
+		},
+	}
+
+	testcases := []struct {
+		pvcName string
+		want    bool
+	}{
+		{pvcName: "data-db-0", want: true},
+		{pvcName: "data-db-12", want: true},
+		{pvcName: "data-db-backup", want: false},
+		{pvcName: "data-dbx-0", want: false},
+		{pvcName: "logs-db-0", want: false},
+	}
+	for _, tc := range testcases {
+		require.Equal(t, tc.want, statefulSetUsesClaims(sts, []string{tc.pvcName}), tc.pvcName)
+	}
+}
+
+func TestReconcileRestartsWorkloads(t *testing.T) {
+	t.Parallel()
+
+	deployment := func(name string, replicas int32, annotations map[string]string, claimName string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: mockNamespace, Annotations: annotations},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.To(replicas),
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Volumes: []corev1.Volume{{
+							Name: "data",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claimName},
+							},
+						}},
+					},
+				},
+			},
+		}
+	}
+
+	volumeReplication := mockVolumeReplicationObj.DeepCopy()
+	volumeReplication.Spec.DataSource.Kind = pvcDataSource
+	volumeReplication.Spec.ReplicationState = replicationv1alpha1.Primary
+	volumeReplication.Spec.RestartWorkloads = true
+	volumeReplication.Status.State = replicationv1alpha1.SecondaryState
+
+	reconciler := createFakeVolumeReplicationReconciler(t,
+		volumeReplication,
+		deployment("app", 1, nil, mockPVCName),
+		deployment("standby", 0, map[string]string{standbyReplicasAnnotation: "2"}, mockPVCName),
+		deployment("other", 1, nil, "other-pvc"),
+		mockVolumeReplicationClassObj.DeepCopy(),
+		mockPersistentVolumeClaim.DeepCopy(),
+		mockPersistentVolume.DeepCopy(),
+	)
+	reconciler.Replication = fake.NewStatefulReplicationClient(1)
+
+	key := types.NamespacedName{Name: volumeReplication.Name, Namespace: volumeReplication.Namespace}
+
+	_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	getDeployment := func(name string) *appsv1.Deployment {
+		d := &appsv1.Deployment{}
+		require.NoError(t, reconciler.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: mockNamespace}, d))
+
+		return d
+	}
+
+	require.NotEmpty(t, getDeployment("app").Spec.Template.Annotations[restartedAtAnnotation])
+	require.Equal(t, int32(2), *getDeployment("standby").Spec.Replicas)
+	require.Empty(t, getDeployment("standby").Spec.Template.Annotations[restartedAtAnnotation])
+	require.Empty(t, getDeployment("other").Spec.Template.Annotations[restartedAtAnnotation])
+
+	latest := &replicationv1alpha1.VolumeReplication{}
+	require.NoError(t, reconciler.Get(context.TODO(), key, latest))
+	require.NotNil(t, latest.Status.WorkloadRestart)
+	require.NotNil(t, latest.Status.WorkloadRestart.Time)
+	require.ElementsMatch(t, []replicationv1alpha1.TouchedWorkload{
+		{Kind: "Deployment", Name: "app", Action: replicationv1alpha1.WorkloadRestarted},
+		{Kind: "Deployment", Name: "standby", Action: replicationv1alpha1.WorkloadScaledUp, Message: "scaled up to 2 replicas"},
+	}, latest.Status.WorkloadRestart.Workloads)
+
+	// the workloads are not restarted again while the volume stays primary
+	app := getDeployment("app")
+	app.Spec.Template.Annotations[restartedAtAnnotation] = "unchanged"
+	require.NoError(t, reconciler.Update(context.TODO(), app))
+
+	_, err = reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	require.Equal(t, "unchanged", getDeployment("app").Spec.Template.Annotations[restartedAtAnnotation])
+}
@@ -83,6 +83,24 @@ func main() {
 	flag.StringVar(&cfg.DriverName, "driver-name", "", "The CSI driver name.")
 	flag.StringVar(&cfg.DriverEndpoint, "csi-address", "/run/csi/socket", "Address of the CSI driver socket.")
 	flag.DurationVar(&cfg.RPCTimeout, "rpc-timeout", defaultTimeout, "The timeout for RPCs to the CSI driver.")
+	flag.DurationVar(&cfg.EnableVerifyInterval, "enable-verify-interval", 0,
+		"The interval after which replication is enabled again to correct drift on the storage side. Zero disables the verification.")
+	flag.Float64Var(&cfg.PollingRPCQPS, "polling-rpc-qps", 0,
+		"The rate of RPCs allowed for polling secondary and resyncing volumes. Zero disables the limit.")
+	flag.IntVar(&cfg.PollingRPCBurst, "polling-rpc-burst", 10, "The burst of RPCs allowed for polling secondary and resyncing volumes.")
+	flag.BoolVar(&cfg.DryRun, "dry-run", false,
+		"Record the replication RPCs in the VolumeReplication status instead of issuing them to the driver.")
+	flag.StringVar(&cfg.PodAdmission, "pod-admission", "",
+		"Validate the pods mounting claims that are secondary or resyncing, \"deny\" rejects them and \"warn\" admits them with a warning. "+
+			"Empty disables the webhook.")
+	flag.BoolVar(&cfg.ClassAdmission, "class-admission", false,
+		"Validate the VolumeReplications using a class their namespace is not permitted to use.")
+	flag.BoolVar(&cfg.BulkOperations, "bulk-operations", false,
+		"Run the VolumeReplicationOperation controller, it is only enabled for a single operator in the cluster.")
+	flag.BoolVar(&cfg.ReplicationPolicies, "replication-policies", false,
+		"Run the VolumeReplicationPolicy controller, it requires the VolumeReplicationPolicy CRD to be installed.")
+	flag.StringVar(&cfg.DefaultReplicationState, "default-replication-state", "primary",
+		"The replication state of the VolumeReplications created for annotated claims, unless their namespace sets another one.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":9998", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
@@ -142,9 +160,10 @@ func main() {
 	}
 
 	err = (&controllers.VolumeReplicationReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("VolumeReplication"),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("VolumeReplication"),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("volume-replication-controller"),
 	}).SetupWithManager(mgr, cfg)
 	if err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "VolumeReplication")
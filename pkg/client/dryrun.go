@@ -0,0 +1,150 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"sort"
+	"sync"
+
+	replicationlib "github.com/csi-addons/spec/lib/go/replication"
+)
+
+// RecordedCall is a replication RPC recorded by the DryRunReplication
+// client. Secret values are never recorded.
+type RecordedCall struct {
+	// Operation is the name of the RPC.
+	Operation string
+	// Source describes the replication source, e.g. "volume:<id>".
+	Source string
+	// ReplicationID is the replication id passed to the RPC.
+	ReplicationID string
+	// Force is the force flag passed to the RPC, if any.
+	Force bool
+	// Parameters are the parameters passed to the RPC.
+	Parameters map[string]string
+	// SecretKeys are the sorted keys of the secrets passed to the RPC.
+	SecretKeys []string
+}
+
+// DryRunReplication is a VolumeReplication that records the RPCs instead of
+// issuing them to the driver. All RPCs succeed and volumes are reported as
+// ready.
+type DryRunReplication struct {
+	mu    sync.Mutex
+	calls []RecordedCall
+}
+
+var _ VolumeReplication = &DryRunReplication{}
+
+// NewDryRunReplication returns a DryRunReplication without recorded calls.
+func NewDryRunReplication() *DryRunReplication {
+	return &DryRunReplication{}
+}
+
+// Calls returns the RPCs recorded so far, in the order they were issued.
+func (dr *DryRunReplication) Calls() []RecordedCall {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+
+	return append([]RecordedCall(nil), dr.calls...)
+}
+
+func (dr *DryRunReplication) record(operation string, replicationSource *replicationlib.ReplicationSource,
+	replicationID string, force bool, secrets, parameters map[string]string,
+) {
+	call := RecordedCall{
+		Operation:     operation,
+		Source:        describeReplicationSource(replicationSource),
+		ReplicationID: replicationID,
+		Force:         force,
+	}
+
+	if len(parameters) > 0 {
+		call.Parameters = make(map[string]string, len(parameters))
+		for k, v := range parameters {
+			call.Parameters[k] = v
+		}
+	}
+
+	for k := range secrets {
+		call.SecretKeys = append(call.SecretKeys, k)
+	}
+	sort.Strings(call.SecretKeys)
+
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+
+	dr.calls = append(dr.calls, call)
+}
+
+// describeReplicationSource returns a short description of the replication
+// source.
+func describeReplicationSource(replicationSource *replicationlib.ReplicationSource) string {
+	switch {
+	case replicationSource.GetVolume() != nil:
+		return "volume:" + replicationSource.GetVolume().GetVolumeId()
+	case replicationSource.GetVolumegroup() != nil:
+		return "volumegroup:" + replicationSource.GetVolumegroup().GetVolumeGroupId()
+	default:
+		return ""
+	}
+}
+
+// EnableVolumeReplication records the EnableVolumeReplication RPC call.
+func (dr *DryRunReplication) EnableVolumeReplication(replicationSource *replicationlib.ReplicationSource, replicationID string,
+	secrets, parameters map[string]string,
+) (*replicationlib.EnableVolumeReplicationResponse, error) {
+	dr.record("EnableVolumeReplication", replicationSource, replicationID, false, secrets, parameters)
+
+	return &replicationlib.EnableVolumeReplicationResponse{}, nil
+}
+
+// DisableVolumeReplication records the DisableVolumeReplication RPC call.
+func (dr *DryRunReplication) DisableVolumeReplication(replicationSource *replicationlib.ReplicationSource, replicationID string,
+	secrets, parameters map[string]string,
+) (*replicationlib.DisableVolumeReplicationResponse, error) {
+	dr.record("DisableVolumeReplication", replicationSource, replicationID, false, secrets, parameters)
+
+	return &replicationlib.DisableVolumeReplicationResponse{}, nil
+}
+
+// PromoteVolume records the PromoteVolume RPC call.
+func (dr *DryRunReplication) PromoteVolume(replicationSource *replicationlib.ReplicationSource, replicationID string,
+	force bool, secrets, parameters map[string]string,
+) (*replicationlib.PromoteVolumeResponse, error) {
+	dr.record("PromoteVolume", replicationSource, replicationID, force, secrets, parameters)
+
+	return &replicationlib.PromoteVolumeResponse{}, nil
+}
+
+// DemoteVolume records the DemoteVolume RPC call.
+func (dr *DryRunReplication) DemoteVolume(replicationSource *replicationlib.ReplicationSource, replicationID string,
+	secrets, parameters map[string]string,
+) (*replicationlib.DemoteVolumeResponse, error) {
+	dr.record("DemoteVolume", replicationSource, replicationID, false, secrets, parameters)
+
+	return &replicationlib.DemoteVolumeResponse{}, nil
+}
+
+// ResyncVolume records the ResyncVolume RPC call.
+func (dr *DryRunReplication) ResyncVolume(replicationSource *replicationlib.ReplicationSource, replicationID string,
+	force bool, secrets, parameters map[string]string,
+) (*replicationlib.ResyncVolumeResponse, error) {
+	dr.record("ResyncVolume", replicationSource, replicationID, force, secrets, parameters)
+
+	return &replicationlib.ResyncVolumeResponse{Ready: true}, nil
+}
@@ -23,44 +23,44 @@ import (
 // ReplicationClient to fake replication operations.
 type ReplicationClient struct {
 	// EnableVolumeReplicationMock mocks EnableVolumeReplication RPC call.
-	EnableVolumeReplicationMock func(volumeID, replicationID string, secrets, parameters map[string]string) (*replicationlib.EnableVolumeReplicationResponse, error)
+	EnableVolumeReplicationMock func(replicationSource *replicationlib.ReplicationSource, replicationID string, secrets, parameters map[string]string) (*replicationlib.EnableVolumeReplicationResponse, error)
 	// DisableVolumeReplicationMock mocks DisableVolumeReplication RPC call.
-	DisableVolumeReplicationMock func(volumeID, replicationID string, secrets, parameters map[string]string) (*replicationlib.DisableVolumeReplicationResponse, error)
+	DisableVolumeReplicationMock func(replicationSource *replicationlib.ReplicationSource, replicationID string, secrets, parameters map[string]string) (*replicationlib.DisableVolumeReplicationResponse, error)
 	// PromoteVolumeMock mocks PromoteVolume RPC call.
-	PromoteVolumeMock func(volumeID, replicationID string, force bool, secrets, parameters map[string]string) (*replicationlib.PromoteVolumeResponse, error)
+	PromoteVolumeMock func(replicationSource *replicationlib.ReplicationSource, replicationID string, force bool, secrets, parameters map[string]string) (*replicationlib.PromoteVolumeResponse, error)
 	// DemoteVolumeMock mocks DemoteVolume RPC call.
-	DemoteVolumeMock func(volumeID, replicationID string, secrets, parameters map[string]string) (*replicationlib.DemoteVolumeResponse, error)
+	DemoteVolumeMock func(replicationSource *replicationlib.ReplicationSource, replicationID string, secrets, parameters map[string]string) (*replicationlib.DemoteVolumeResponse, error)
 	// ResyncVolumeMock mocks ResyncVolume RPC call.
-	ResyncVolumeMock func(volumeID, replicationID string, secrets, parameters map[string]string) (*replicationlib.ResyncVolumeResponse, error)
+	ResyncVolumeMock func(replicationSource *replicationlib.ReplicationSource, replicationID string, force bool, secrets, parameters map[string]string) (*replicationlib.ResyncVolumeResponse, error)
 }
 
 // EnableVolumeReplication calls EnableVolumeReplicationMock mock function.
 func (rc *ReplicationClient) EnableVolumeReplication(
-	volumeID,
+	replicationSource *replicationlib.ReplicationSource,
 	replicationID string,
 	secrets,
 	parameters map[string]string) (
 	*replicationlib.EnableVolumeReplicationResponse,
 	error,
 ) {
-	return rc.EnableVolumeReplicationMock(volumeID, replicationID, secrets, parameters)
+	return rc.EnableVolumeReplicationMock(replicationSource, replicationID, secrets, parameters)
 }
 
 // DisableVolumeReplication calls DisableVolumeReplicationMock mock function.
 func (rc *ReplicationClient) DisableVolumeReplication(
-	volumeID,
+	replicationSource *replicationlib.ReplicationSource,
 	replicationID string,
 	secrets,
 	parameters map[string]string) (
 	*replicationlib.DisableVolumeReplicationResponse,
 	error,
 ) {
-	return rc.DisableVolumeReplicationMock(volumeID, replicationID, secrets, parameters)
+	return rc.DisableVolumeReplicationMock(replicationSource, replicationID, secrets, parameters)
 }
 
 // PromoteVolume calls PromoteVolumeMock mock function.
 func (rc *ReplicationClient) PromoteVolume(
-	volumeID,
+	replicationSource *replicationlib.ReplicationSource,
 	replicationID string,
 	force bool,
 	secrets,
@@ -68,29 +68,30 @@ func (rc *ReplicationClient) PromoteVolume(
 	*replicationlib.PromoteVolumeResponse,
 	error,
 ) {
-	return rc.PromoteVolumeMock(volumeID, replicationID, force, secrets, parameters)
+	return rc.PromoteVolumeMock(replicationSource, replicationID, force, secrets, parameters)
 }
 
 // DemoteVolume calls DemoteVolumeMock mock function.
 func (rc *ReplicationClient) DemoteVolume(
-	volumeID,
+	replicationSource *replicationlib.ReplicationSource,
 	replicationID string,
 	secrets,
 	parameters map[string]string) (
 	*replicationlib.DemoteVolumeResponse,
 	error,
 ) {
-	return rc.DemoteVolumeMock(volumeID, replicationID, secrets, parameters)
+	return rc.DemoteVolumeMock(replicationSource, replicationID, secrets, parameters)
 }
 
 // ResyncVolume calls ResyncVolumeMock function.
 func (rc *ReplicationClient) ResyncVolume(
-	volumeID,
+	replicationSource *replicationlib.ReplicationSource,
 	replicationID string,
+	force bool,
 	secrets,
 	parameters map[string]string) (
 	*replicationlib.ResyncVolumeResponse,
 	error,
 ) {
-	return rc.ResyncVolumeMock(volumeID, replicationID, secrets, parameters)
+	return rc.ResyncVolumeMock(replicationSource, replicationID, force, secrets, parameters)
 }
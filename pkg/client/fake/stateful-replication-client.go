@@ -0,0 +1,388 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"sync"
+
+	replicationlib "github.com/csi-addons/spec/lib/go/replication"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Operation is the name of a replication RPC.
+type Operation string
+
+const (
+	// EnableVolumeReplication is the EnableVolumeReplication RPC.
+	EnableVolumeReplication Operation = "EnableVolumeReplication"
+	// DisableVolumeReplication is the DisableVolumeReplication RPC.
+	DisableVolumeReplication Operation = "DisableVolumeReplication"
+	// PromoteVolume is the PromoteVolume RPC.
+	PromoteVolume Operation = "PromoteVolume"
+	// DemoteVolume is the DemoteVolume RPC.
+	DemoteVolume Operation = "DemoteVolume"
+	// ResyncVolume is the ResyncVolume RPC.
+	ResyncVolume Operation = "ResyncVolume"
+)
+
+// Role is the replication role of a volume.
+type Role string
+
+const (
+	// Primary is the role of a promoted volume.
+	Primary Role = "primary"
+	// Secondary is the role of a demoted volume.
+	Secondary Role = "secondary"
+)
+
+// VolumeState is the replication state of a single replication source.
+type VolumeState struct {
+	// ReplicationID is the replication id the replication was enabled with.
+	ReplicationID string `json:"replicationID,omitempty"`
+	// Role is the replication role of the volume.
+	Role Role `json:"role"`
+	// Resyncing is true while a resync is in progress.
+	Resyncing bool `json:"resyncing,omitempty"`
+	// SplitBrain is true if the volume diverged from its peer, it is only
+	// cleared by a forced resync.
+	SplitBrain bool `json:"splitBrain,omitempty"`
+	// Polls is the number of ResyncVolume calls since the resync started.
+	Polls int `json:"polls,omitempty"`
+}
+
+// Fault fails calls of an operation with a gRPC error.
+type Fault struct {
+	// Operation is the failing operation.
+	Operation Operation
+	// Source restricts the fault to a replication source, a nil source
+	// matches all sources.
+	Source *replicationlib.ReplicationSource
+	// Code is the gRPC code of the returned error.
+	Code codes.Code
+	// Message is the message of the returned error.
+	Message string
+	// Times is the number of calls that fail, zero fails all calls until
+	// the faults are cleared.
+	Times int
+}
+
+// StatefulReplicationClient is a VolumeReplication that keeps the
+// replication state of each source in memory. Volumes are primary once
+// replication is enabled, and a resync reports the volume ready after a
+// configurable number of polls. Faults can be injected per operation.
+type StatefulReplicationClient struct {
+	mu         sync.Mutex
+	readyAfter int
+	volumes    map[string]*VolumeState
+	faults     []*Fault
+	calls      []Operation
+}
+
+// NewStatefulReplicationClient returns a StatefulReplicationClient without
+// any enabled volume. A resync reports the volume ready on the readyAfter'th
+// ResyncVolume call.
+func NewStatefulReplicationClient(readyAfter int) *StatefulReplicationClient {
+	return &StatefulReplicationClient{
+		readyAfter: readyAfter,
+		volumes:    make(map[string]*VolumeState),
+	}
+}
+
+// VolumeSource returns the replication source of a volume.
+func VolumeSource(volumeID string) *replicationlib.ReplicationSource {
+	return &replicationlib.ReplicationSource{
+		Type: &replicationlib.ReplicationSource_Volume{
+			Volume: &replicationlib.ReplicationSource_VolumeSource{VolumeId: volumeID},
+		},
+	}
+}
+
+// VolumeGroupSource returns the replication source of a volume group.
+func VolumeGroupSource(volumeGroupID string) *replicationlib.ReplicationSource {
+	return &replicationlib.ReplicationSource{
+		Type: &replicationlib.ReplicationSource_Volumegroup{
+			Volumegroup: &replicationlib.ReplicationSource_VolumeGroupSource{VolumeGroupId: volumeGroupID},
+		},
+	}
+}
+
+// sourceKey returns the key of the replication source in the state, or an
+// empty string if the source has no id.
+func sourceKey(replicationSource *replicationlib.ReplicationSource) string {
+	if id := replicationSource.GetVolume().GetVolumeId(); id != "" {
+		return "volume/" + id
+	}
+
+	if id := replicationSource.GetVolumegroup().GetVolumeGroupId(); id != "" {
+		return "volumegroup/" + id
+	}
+
+	return ""
+}
+
+// State returns a copy of the replication state of the source, and false if
+// replication is not enabled for it.
+func (sc *StatefulReplicationClient) State(replicationSource *replicationlib.ReplicationSource) (VolumeState, bool) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	state, ok := sc.volumes[sourceKey(replicationSource)]
+	if !ok {
+		return VolumeState{}, false
+	}
+
+	return *state, true
+}
+
+// SetState sets the replication state of the source, e.g. to start from a
+// secondary or split brain volume.
+func (sc *StatefulReplicationClient) SetState(replicationSource *replicationlib.ReplicationSource, state VolumeState) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	sc.volumes[sourceKey(replicationSource)] = &state
+}
+
+// States returns a copy of the replication state of all enabled sources,
+// keyed by "volume/<id>" or "volumegroup/<id>".
+func (sc *StatefulReplicationClient) States() map[string]VolumeState {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	states := make(map[string]VolumeState, len(sc.volumes))
+	for key, state := range sc.volumes {
+		states[key] = *state
+	}
+
+	return states
+}
+
+// SetStates replaces the replication state of all sources, keyed as
+// returned by States.
+func (sc *StatefulReplicationClient) SetStates(states map[string]VolumeState) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	sc.volumes = make(map[string]*VolumeState, len(states))
+	for key, state := range states {
+		sc.volumes[key] = &state
+	}
+}
+
+// InjectFault adds a fault, faults are matched in the order they were added.
+func (sc *StatefulReplicationClient) InjectFault(fault Fault) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	sc.faults = append(sc.faults, &fault)
+}
+
+// ClearFaults removes all injected faults.
+func (sc *StatefulReplicationClient) ClearFaults() {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	sc.faults = nil
+}
+
+// Calls returns the operations called so far, in order.
+func (sc *StatefulReplicationClient) Calls() []Operation {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	return append([]Operation(nil), sc.calls...)
+}
+
+// begin records the call and returns the state key of the source, or an
+// error if the source is invalid or a fault matches. It must be called with
+// the lock held.
+func (sc *StatefulReplicationClient) begin(op Operation, replicationSource *replicationlib.ReplicationSource) (string, error) {
+	sc.calls = append(sc.calls, op)
+
+	key := sourceKey(replicationSource)
+
+	for i, fault := range sc.faults {
+		if fault.Operation != op || (fault.Source != nil && sourceKey(fault.Source) != key) {
+			continue
+		}
+
+		if fault.Times > 0 {
+			fault.Times--
+			if fault.Times == 0 {
+				sc.faults = append(sc.faults[:i], sc.faults[i+1:]...)
+			}
+		}
+
+		return "", status.Error(fault.Code, fault.Message)
+	}
+
+	if key == "" {
+		return "", status.Error(codes.InvalidArgument, "replication source is missing")
+	}
+
+	return key, nil
+}
+
+// enabledState returns the state of an enabled source. It must be called
+// with the lock held.
+func (sc *StatefulReplicationClient) enabledState(key string) (*VolumeState, error) {
+	state, ok := sc.volumes[key]
+	if !ok {
+		return nil, status.Errorf(codes.FailedPrecondition, "replication is not enabled for %s", key)
+	}
+
+	return state, nil
+}
+
+// EnableVolumeReplication enables the replication of the source as primary.
+// Enabling an enabled source succeeds without changing its state.
+func (sc *StatefulReplicationClient) EnableVolumeReplication(replicationSource *replicationlib.ReplicationSource,
+	replicationID string, _, _ map[string]string,
+) (*replicationlib.EnableVolumeReplicationResponse, error) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	key, err := sc.begin(EnableVolumeReplication, replicationSource)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := sc.volumes[key]; !ok {
+		sc.volumes[key] = &VolumeState{ReplicationID: replicationID, Role: Primary}
+	}
+
+	return &replicationlib.EnableVolumeReplicationResponse{}, nil
+}
+
+// DisableVolumeReplication disables the replication of the source, it fails
+// with NotFound if replication is not enabled.
+func (sc *StatefulReplicationClient) DisableVolumeReplication(replicationSource *replicationlib.ReplicationSource,
+	_ string, _, _ map[string]string,
+) (*replicationlib.DisableVolumeReplicationResponse, error) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	key, err := sc.begin(DisableVolumeReplication, replicationSource)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := sc.volumes[key]; !ok {
+		return nil, status.Errorf(codes.NotFound, "replication is not enabled for %s", key)
+	}
+
+	delete(sc.volumes, key)
+
+	return &replicationlib.DisableVolumeReplicationResponse{}, nil
+}
+
+// PromoteVolume promotes the source to primary. A split brain volume can
+// only be promoted with force.
+func (sc *StatefulReplicationClient) PromoteVolume(replicationSource *replicationlib.ReplicationSource,
+	_ string, force bool, _, _ map[string]string,
+) (*replicationlib.PromoteVolumeResponse, error) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	key, err := sc.begin(PromoteVolume, replicationSource)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := sc.enabledState(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if state.SplitBrain && !force {
+		return nil, status.Errorf(codes.FailedPrecondition, "%s is in split brain", key)
+	}
+
+	state.Role = Primary
+	state.Resyncing = false
+	state.Polls = 0
+
+	return &replicationlib.PromoteVolumeResponse{}, nil
+}
+
+// DemoteVolume demotes the source to secondary.
+func (sc *StatefulReplicationClient) DemoteVolume(replicationSource *replicationlib.ReplicationSource,
+	_ string, _, _ map[string]string,
+) (*replicationlib.DemoteVolumeResponse, error) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	key, err := sc.begin(DemoteVolume, replicationSource)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := sc.enabledState(key)
+	if err != nil {
+		return nil, err
+	}
+
+	state.Role = Secondary
+
+	return &replicationlib.DemoteVolumeResponse{}, nil
+}
+
+// ResyncVolume polls the resync of a secondary source. A resync is started
+// when forced, and the volume is ready once the resync reached the
+// configured number of polls. Without a resync in progress the volume is
+// ready unless it is in split brain.
+func (sc *StatefulReplicationClient) ResyncVolume(replicationSource *replicationlib.ReplicationSource,
+	_ string, force bool, _, _ map[string]string,
+) (*replicationlib.ResyncVolumeResponse, error) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	key, err := sc.begin(ResyncVolume, replicationSource)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := sc.enabledState(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if state.Role != Secondary {
+		return nil, status.Errorf(codes.FailedPrecondition, "%s is not secondary", key)
+	}
+
+	if !state.Resyncing {
+		if !force {
+			return &replicationlib.ResyncVolumeResponse{Ready: !state.SplitBrain}, nil
+		}
+
+		state.Resyncing = true
+		state.Polls = 0
+	}
+
+	state.Polls++
+	if state.Polls < sc.readyAfter {
+		return &replicationlib.ResyncVolumeResponse{Ready: false}, nil
+	}
+
+	state.Resyncing = false
+	state.SplitBrain = false
+	state.Polls = 0
+
+	return &replicationlib.ResyncVolumeResponse{Ready: true}, nil
+}
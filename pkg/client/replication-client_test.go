@@ -24,32 +24,36 @@ import (
 
 	replicationlib "github.com/csi-addons/spec/lib/go/replication"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+var _ VolumeReplication = &fake.ReplicationClient{}
+
 func TestEnableVolumeReplication(t *testing.T) {
 	t.Parallel()
 
 	mockedEnableReplication := &fake.ReplicationClient{
-		EnableVolumeReplicationMock: func(_, _ string, _, _ map[string]string) (*replicationlib.EnableVolumeReplicationResponse, error) {
+		EnableVolumeReplicationMock: func(_ *replicationlib.ReplicationSource, _ string, _, _ map[string]string) (*replicationlib.EnableVolumeReplicationResponse, error) {
 			return &replicationlib.EnableVolumeReplicationResponse{}, nil
 		},
 	}
 	client := mockedEnableReplication
 
-	resp, err := client.EnableVolumeReplication("", "", nil, nil)
+	resp, err := client.EnableVolumeReplication(nil, "", nil, nil)
 	require.Equal(t, &replicationlib.EnableVolumeReplicationResponse{}, resp)
 	require.NoError(t, err)
 
 	// return error
 	mockedEnableReplication = &fake.ReplicationClient{
-		EnableVolumeReplicationMock: func(_, _ string, _, _ map[string]string) (*replicationlib.EnableVolumeReplicationResponse, error) {
+		EnableVolumeReplicationMock: func(_ *replicationlib.ReplicationSource, _ string, _, _ map[string]string) (*replicationlib.EnableVolumeReplicationResponse, error) {
 			return nil, errors.New("failed to enable mirroring")
 		},
 	}
 
 	client = mockedEnableReplication
 
-	resp, err = client.EnableVolumeReplication("", "", nil, nil)
+	resp, err = client.EnableVolumeReplication(nil, "", nil, nil)
 	require.Nil(t, resp)
 	require.Error(t, err)
 }
@@ -58,26 +62,26 @@ func TestDisableVolumeReplication(t *testing.T) {
 	t.Parallel()
 
 	mockedDisableReplication := &fake.ReplicationClient{
-		DisableVolumeReplicationMock: func(_, _ string, _, _ map[string]string) (*replicationlib.DisableVolumeReplicationResponse, error) {
+		DisableVolumeReplicationMock: func(_ *replicationlib.ReplicationSource, _ string, _, _ map[string]string) (*replicationlib.DisableVolumeReplicationResponse, error) {
 			return &replicationlib.DisableVolumeReplicationResponse{}, nil
 		},
 	}
 	client := mockedDisableReplication
 
-	resp, err := client.DisableVolumeReplication("", "", nil, nil)
+	resp, err := client.DisableVolumeReplication(nil, "", nil, nil)
 	require.Equal(t, &replicationlib.DisableVolumeReplicationResponse{}, resp)
 	require.NoError(t, err)
 
 	// return error
 	mockedDisableReplication = &fake.ReplicationClient{
-		DisableVolumeReplicationMock: func(_, _ string, _, _ map[string]string) (*replicationlib.DisableVolumeReplicationResponse, error) {
+		DisableVolumeReplicationMock: func(_ *replicationlib.ReplicationSource, _ string, _, _ map[string]string) (*replicationlib.DisableVolumeReplicationResponse, error) {
 			return nil, errors.New("failed to disable mirroring")
 		},
 	}
 
 	client = mockedDisableReplication
 
-	resp, err = client.DisableVolumeReplication("", "", nil, nil)
+	resp, err = client.DisableVolumeReplication(nil, "", nil, nil)
 	require.Nil(t, resp)
 	require.Error(t, err)
 }
@@ -86,26 +90,26 @@ func TestPromoteVolume(t *testing.T) {
 	t.Parallel()
 	// return success response
 	mockedPromoteVolume := &fake.ReplicationClient{
-		PromoteVolumeMock: func(_, _ string, _ bool, _, _ map[string]string) (*replicationlib.PromoteVolumeResponse, error) {
+		PromoteVolumeMock: func(_ *replicationlib.ReplicationSource, _ string, _ bool, _, _ map[string]string) (*replicationlib.PromoteVolumeResponse, error) {
 			return &replicationlib.PromoteVolumeResponse{}, nil
 		},
 	}
 	client := mockedPromoteVolume
 
-	resp, err := client.PromoteVolume("", "", false, nil, nil)
+	resp, err := client.PromoteVolume(nil, "", false, nil, nil)
 	require.Equal(t, &replicationlib.PromoteVolumeResponse{}, resp)
 	require.NoError(t, err)
 
 	// return error
 	mockedPromoteVolume = &fake.ReplicationClient{
-		PromoteVolumeMock: func(_, _ string, _ bool, _, _ map[string]string) (*replicationlib.PromoteVolumeResponse, error) {
+		PromoteVolumeMock: func(_ *replicationlib.ReplicationSource, _ string, _ bool, _, _ map[string]string) (*replicationlib.PromoteVolumeResponse, error) {
 			return nil, errors.New("failed to promote volume")
 		},
 	}
 
 	client = mockedPromoteVolume
 
-	resp, err = client.PromoteVolume("", "", false, nil, nil)
+	resp, err = client.PromoteVolume(nil, "", false, nil, nil)
 	require.Nil(t, resp)
 	require.Error(t, err)
 }
@@ -114,26 +118,26 @@ func TestDemoteVolume(t *testing.T) {
 	t.Parallel()
 	// return success response
 	mockedDemoteVolume := &fake.ReplicationClient{
-		DemoteVolumeMock: func(_, _ string, _, _ map[string]string) (*replicationlib.DemoteVolumeResponse, error) {
+		DemoteVolumeMock: func(_ *replicationlib.ReplicationSource, _ string, _, _ map[string]string) (*replicationlib.DemoteVolumeResponse, error) {
 			return &replicationlib.DemoteVolumeResponse{}, nil
 		},
 	}
 	client := mockedDemoteVolume
 
-	resp, err := client.DemoteVolume("", "", nil, nil)
+	resp, err := client.DemoteVolume(nil, "", nil, nil)
 	require.Equal(t, &replicationlib.DemoteVolumeResponse{}, resp)
 	require.NoError(t, err)
 
 	// return error
 	mockedDemoteVolume = &fake.ReplicationClient{
-		DemoteVolumeMock: func(_, _ string, _, _ map[string]string) (*replicationlib.DemoteVolumeResponse, error) {
+		DemoteVolumeMock: func(_ *replicationlib.ReplicationSource, _ string, _, _ map[string]string) (*replicationlib.DemoteVolumeResponse, error) {
 			return nil, errors.New("failed to demote volume")
 		},
 	}
 
 	client = mockedDemoteVolume
 
-	resp, err = client.DemoteVolume("", "", nil, nil)
+	resp, err = client.DemoteVolume(nil, "", nil, nil)
 	require.Nil(t, resp)
 	require.Error(t, err)
 }
@@ -142,26 +146,157 @@ func TestResyncVolume(t *testing.T) {
 	t.Parallel()
 	// return success response
 	mockedResyncVolume := &fake.ReplicationClient{
-		ResyncVolumeMock: func(_, _ string, _, _ map[string]string) (*replicationlib.ResyncVolumeResponse, error) {
+		ResyncVolumeMock: func(_ *replicationlib.ReplicationSource, _ string, _ bool, _, _ map[string]string) (*replicationlib.ResyncVolumeResponse, error) {
 			return &replicationlib.ResyncVolumeResponse{}, nil
 		},
 	}
 	client := mockedResyncVolume
 
-	resp, err := client.ResyncVolume("", "", nil, nil)
+	resp, err := client.ResyncVolume(nil, "", false, nil, nil)
 	require.Equal(t, &replicationlib.ResyncVolumeResponse{}, resp)
 	require.NoError(t, err)
 
 	// return error
 	mockedResyncVolume = &fake.ReplicationClient{
-		ResyncVolumeMock: func(_, _ string, _, _ map[string]string) (*replicationlib.ResyncVolumeResponse, error) {
+		ResyncVolumeMock: func(_ *replicationlib.ReplicationSource, _ string, _ bool, _, _ map[string]string) (*replicationlib.ResyncVolumeResponse, error) {
 			return nil, errors.New("failed to resync volume")
 		},
 	}
 
 	client = mockedResyncVolume
 
-	resp, err = client.ResyncVolume("", "", nil, nil)
+	resp, err = client.ResyncVolume(nil, "", false, nil, nil)
 	require.Nil(t, resp)
 	require.Error(t, err)
 }
+
+func TestDryRunReplication(t *testing.T) {
+	t.Parallel()
+
+	client := NewDryRunReplication()
+	source := &replicationlib.ReplicationSource{
+		Type: &replicationlib.ReplicationSource_Volume{
+			Volume: &replicationlib.ReplicationSource_VolumeSource{VolumeId: "volume-id"},
+		},
+	}
+
+	_, err := client.EnableVolumeReplication(source, "replication-id",
+		map[string]string{"password": "secret", "user": "admin"}, map[string]string{"mode": "snapshot"})
+	require.NoError(t, err)
+
+	resp, err := client.ResyncVolume(source, "", true, nil, nil)
+	require.NoError(t, err)
+	require.True(t, resp.GetReady())
+
+	require.Equal(t, []RecordedCall{
+		{
+			Operation:     "EnableVolumeReplication",
+			Source:        "volume:volume-id",
+			ReplicationID: "replication-id",
+			Parameters:    map[string]string{"mode": "snapshot"},
+			SecretKeys:    []string{"password", "user"},
+		},
+		{Operation: "ResyncVolume", Source: "volume:volume-id", Force: true},
+	}, client.Calls())
+}
+
+func TestStatefulReplicationClient(t *testing.T) {
+	t.Parallel()
+
+	var client VolumeReplication = fake.NewStatefulReplicationClient(2)
+
+	stateful, ok := client.(*fake.StatefulReplicationClient)
+	require.True(t, ok)
+
+	source := fake.VolumeSource("volume-id")
+
+	// replication needs to be enabled first
+	_, err := client.DemoteVolume(source, "", nil, nil)
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = client.EnableVolumeReplication(source, "", nil, nil)
+	require.NoError(t, err)
+	// enable is idempotent
+	_, err = client.EnableVolumeReplication(source, "", nil, nil)
+	require.NoError(t, err)
+
+	state, ok := stateful.State(source)
+	require.True(t, ok)
+	require.Equal(t, fake.Primary, state.Role)
+
+	// a primary volume cannot be resynced
+	_, err = client.ResyncVolume(source, "", true, nil, nil)
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = client.DemoteVolume(source, "", nil, nil)
+	require.NoError(t, err)
+
+	resp, err := client.ResyncVolume(source, "", true, nil, nil)
+	require.NoError(t, err)
+	require.False(t, resp.GetReady())
+
+	resp, err = client.ResyncVolume(source, "", false, nil, nil)
+	require.NoError(t, err)
+	require.True(t, resp.GetReady())
+
+	// split brain volumes are only promoted with force
+	stateful.SetState(source, fake.VolumeState{Role: fake.Secondary, SplitBrain: true})
+
+	_, err = client.PromoteVolume(source, "", false, nil, nil)
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = client.PromoteVolume(source, "", true, nil, nil)
+	require.NoError(t, err)
+
+	_, err = client.DisableVolumeReplication(source, "", nil, nil)
+	require.NoError(t, err)
+
+	_, err = client.DisableVolumeReplication(source, "", nil, nil)
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestStatefulReplicationClientFaults(t *testing.T) {
+	t.Parallel()
+
+	client := fake.NewStatefulReplicationClient(1)
+	source := fake.VolumeSource("volume-id")
+	otherSource := fake.VolumeGroupSource("volume-group-id")
+
+	client.InjectFault(fake.Fault{
+		Operation: fake.EnableVolumeReplication,
+		Source:    source,
+		Code:      codes.Unavailable,
+		Times:     1,
+	})
+
+	_, err := client.EnableVolumeReplication(otherSource, "", nil, nil)
+	require.NoError(t, err)
+
+	_, err = client.EnableVolumeReplication(source, "", nil, nil)
+	require.Equal(t, codes.Unavailable, status.Code(err))
+
+	// the fault is used up
+	_, err = client.EnableVolumeReplication(source, "", nil, nil)
+	require.NoError(t, err)
+
+	client.InjectFault(fake.Fault{Operation: fake.PromoteVolume, Code: codes.Internal})
+
+	for range 2 {
+		_, err = client.PromoteVolume(source, "", false, nil, nil)
+		require.Equal(t, codes.Internal, status.Code(err))
+	}
+
+	client.ClearFaults()
+
+	_, err = client.PromoteVolume(source, "", false, nil, nil)
+	require.NoError(t, err)
+
+	require.Equal(t, []fake.Operation{
+		fake.EnableVolumeReplication,
+		fake.EnableVolumeReplication,
+		fake.EnableVolumeReplication,
+		fake.PromoteVolume,
+		fake.PromoteVolume,
+		fake.PromoteVolume,
+	}, client.Calls())
+}
@@ -18,6 +18,7 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"time"
 )
 
@@ -29,8 +30,48 @@ type DriverConfig struct {
 	DriverName string
 	// RPCTimeout for RPCs to the CSI driver.
 	RPCTimeout time.Duration
+	// EnableVerifyInterval is the interval after which replication is
+	// enabled again even if its inputs did not change, to correct drift on
+	// the storage side. Zero disables the verification.
+	EnableVerifyInterval time.Duration
+	// PollingRPCQPS is the rate of RPCs allowed for polling secondary and
+	// resyncing volumes. Zero disables the limit.
+	PollingRPCQPS float64
+	// PollingRPCBurst is the burst of RPCs allowed for polling secondary and
+	// resyncing volumes.
+	PollingRPCBurst int
+	// DryRun records the replication RPCs instead of issuing them to the
+	// driver, for all VolumeReplications.
+	DryRun bool
+	// PodAdmission is the mode of the validating webhook checking the pods
+	// mounting claims that are not primary, it is disabled when empty.
+	PodAdmission string
+	// ClassAdmission serves the validating webhook rejecting the
+	// VolumeReplications using a class their namespace is not permitted to
+	// use.
+	ClassAdmission bool
+	// BulkOperations runs the VolumeReplicationOperation controller. It
+	// applies to the VolumeReplications of all drivers, so it is only
+	// enabled for a single operator.
+	BulkOperations bool
+	// ReplicationPolicies runs the VolumeReplicationPolicy controller, which
+	// requires the VolumeReplicationPolicy CRD.
+	ReplicationPolicies bool
+	// DefaultReplicationState is the replication state of the
+	// VolumeReplications created for annotated claims, unless their
+	// namespace sets another one.
+	DefaultReplicationState string
 }
 
+const (
+	// PodAdmissionDeny rejects the pods mounting claims that are not
+	// primary.
+	PodAdmissionDeny = "deny"
+	// PodAdmissionWarn admits the pods mounting claims that are not primary
+	// with a warning.
+	PodAdmissionWarn = "warn"
+)
+
 // NewDriverConfig returns the newly initialized DriverConfig.
 func NewDriverConfig() *DriverConfig {
 	return &DriverConfig{}
@@ -43,5 +84,19 @@ func (cfg *DriverConfig) Validate() error {
 		return errors.New("driverName is empty")
 	}
 
+	switch cfg.PodAdmission {
+	case "", PodAdmissionDeny, PodAdmissionWarn:
+	default:
+		return fmt.Errorf("invalid pod admission mode %q, must be %q or %q",
+			cfg.PodAdmission, PodAdmissionDeny, PodAdmissionWarn)
+	}
+
+	switch cfg.DefaultReplicationState {
+	case "", "primary", "secondary":
+	default:
+		return fmt.Errorf("invalid default replication state %q, must be \"primary\" or \"secondary\"",
+			cfg.DefaultReplicationState)
+	}
+
 	return nil
 }
@@ -0,0 +1,331 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package conformance checks that a replication driver implements the
+// CSI-Addons replication RPCs the way the operator expects. It runs a
+// scripted replication lifecycle against a single volume, checking the
+// idempotency of the RPCs and the gRPC codes of their errors.
+package conformance
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	grpcClient "github.com/csi-addons/volume-replication-operator/pkg/client"
+
+	replicationlib "github.com/csi-addons/spec/lib/go/replication"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Config configures the conformance suite.
+type Config struct {
+	// ReplicationSource is the existing volume or volume group the
+	// lifecycle is run against. Replication must not be enabled for it.
+	ReplicationSource *replicationlib.ReplicationSource
+	// ReplicationID is the replication id passed to the RPCs.
+	ReplicationID string
+	// Parameters are passed to the RPCs.
+	Parameters map[string]string
+	// Secrets are passed to the RPCs.
+	Secrets map[string]string
+	// ResyncPolls is the maximum number of ResyncVolume calls to wait for
+	// the volume to be ready.
+	ResyncPolls int
+	// ResyncInterval is the interval between ResyncVolume calls.
+	ResyncInterval time.Duration
+}
+
+// Result is the outcome of a single check.
+type Result struct {
+	// Name of the check.
+	Name string
+	// Duration of the check.
+	Duration time.Duration
+	// Failure is the reason the check failed, empty if it passed.
+	Failure string
+	// Skipped is the reason the check was skipped, empty if it ran.
+	Skipped string
+}
+
+// Report holds the results of all checks, in the order they ran.
+type Report struct {
+	Results  []Result
+	Duration time.Duration
+}
+
+// Failed returns true if any check failed.
+func (r *Report) Failed() bool {
+	return slices.ContainsFunc(r.Results, func(result Result) bool {
+		return result.Failure != ""
+	})
+}
+
+// check is a single step of the suite.
+type check struct {
+	name string
+	run  func(s *suite) error
+}
+
+// suite runs the checks against a driver.
+type suite struct {
+	cfg         Config
+	replication grpcClient.VolumeReplication
+}
+
+// lifecycle are the checks run against the volume, in order. Once a check
+// fails the remaining ones are skipped, as they depend on the state the
+// failed check should have left the volume in.
+var lifecycle = []check{
+	{
+		name: "EnableVolumeReplication enables the replication",
+		run: func(s *suite) error {
+			_, err := s.replication.EnableVolumeReplication(s.cfg.ReplicationSource, s.cfg.ReplicationID, s.cfg.Secrets, s.cfg.Parameters)
+
+			return err
+		},
+	},
+	{
+		name: "EnableVolumeReplication is idempotent",
+		run: func(s *suite) error {
+			_, err := s.replication.EnableVolumeReplication(s.cfg.ReplicationSource, s.cfg.ReplicationID, s.cfg.Secrets, s.cfg.Parameters)
+
+			return err
+		},
+	},
+	{
+		name: "PromoteVolume succeeds for a primary volume",
+		run: func(s *suite) error {
+			_, err := s.replication.PromoteVolume(s.cfg.ReplicationSource, s.cfg.ReplicationID, false, s.cfg.Secrets, s.cfg.Parameters)
+
+			return err
+		},
+	},
+	{
+		name: "DemoteVolume demotes the volume",
+		run: func(s *suite) error {
+			_, err := s.replication.DemoteVolume(s.cfg.ReplicationSource, s.cfg.ReplicationID, s.cfg.Secrets, s.cfg.Parameters)
+
+			return err
+		},
+	},
+	{
+		name: "DemoteVolume is idempotent",
+		run: func(s *suite) error {
+			_, err := s.replication.DemoteVolume(s.cfg.ReplicationSource, s.cfg.ReplicationID, s.cfg.Secrets, s.cfg.Parameters)
+
+			return err
+		},
+	},
+	{
+		name: "ResyncVolume reports a secondary volume ready",
+		run:  (*suite).waitForResync,
+	},
+	{
+		name: "PromoteVolume promotes a secondary volume",
+		run: func(s *suite) error {
+			_, err := s.replication.PromoteVolume(s.cfg.ReplicationSource, s.cfg.ReplicationID, false, s.cfg.Secrets, s.cfg.Parameters)
+			// the operator promotes with force on FailedPrecondition
+			if status.Code(err) == codes.FailedPrecondition {
+				_, err = s.replication.PromoteVolume(s.cfg.ReplicationSource, s.cfg.ReplicationID, true, s.cfg.Secrets, s.cfg.Parameters)
+			}
+
+			return err
+		},
+	},
+	{
+		name: "DisableVolumeReplication disables the replication",
+		run: func(s *suite) error {
+			_, err := s.replication.DisableVolumeReplication(s.cfg.ReplicationSource, s.cfg.ReplicationID, s.cfg.Secrets, s.cfg.Parameters)
+
+			return err
+		},
+	},
+	{
+		name: "DisableVolumeReplication is idempotent or fails with NotFound",
+		run: func(s *suite) error {
+			_, err := s.replication.DisableVolumeReplication(s.cfg.ReplicationSource, s.cfg.ReplicationID, s.cfg.Secrets, s.cfg.Parameters)
+
+			return expectCode(err, codes.OK, codes.NotFound)
+		},
+	},
+	{
+		name: "PromoteVolume fails with FailedPrecondition or NotFound without replication",
+		run: func(s *suite) error {
+			_, err := s.replication.PromoteVolume(s.cfg.ReplicationSource, s.cfg.ReplicationID, false, s.cfg.Secrets, s.cfg.Parameters)
+
+			return expectCode(err, codes.FailedPrecondition, codes.NotFound)
+		},
+	},
+}
+
+// cleanup disables the replication a failed lifecycle might have left
+// enabled. It is only reported when it fails.
+var cleanup = check{
+	name: "DisableVolumeReplication cleans up the replication",
+	run: func(s *suite) error {
+		_, err := s.replication.DisableVolumeReplication(s.cfg.ReplicationSource, s.cfg.ReplicationID, s.cfg.Secrets, s.cfg.Parameters)
+
+		return expectCode(err, codes.OK, codes.NotFound)
+	},
+}
+
+// contracts are independent checks of the error codes.
+var contracts = []check{
+	{
+		name: "RPCs fail with InvalidArgument without a replication source",
+		run: func(s *suite) error {
+			calls := []struct {
+				operation string
+				call      func() error
+			}{
+				{"EnableVolumeReplication", func() error {
+					_, err := s.replication.EnableVolumeReplication(nil, s.cfg.ReplicationID, s.cfg.Secrets, s.cfg.Parameters)
+
+					return err
+				}},
+				{"DisableVolumeReplication", func() error {
+					_, err := s.replication.DisableVolumeReplication(nil, s.cfg.ReplicationID, s.cfg.Secrets, s.cfg.Parameters)
+
+					return err
+				}},
+				{"PromoteVolume", func() error {
+					_, err := s.replication.PromoteVolume(nil, s.cfg.ReplicationID, false, s.cfg.Secrets, s.cfg.Parameters)
+
+					return err
+				}},
+				{"DemoteVolume", func() error {
+					_, err := s.replication.DemoteVolume(nil, s.cfg.ReplicationID, s.cfg.Secrets, s.cfg.Parameters)
+
+					return err
+				}},
+				{"ResyncVolume", func() error {
+					_, err := s.replication.ResyncVolume(nil, s.cfg.ReplicationID, false, s.cfg.Secrets, s.cfg.Parameters)
+
+					return err
+				}},
+			}
+
+			for _, c := range calls {
+				err := expectCode(c.call(), codes.InvalidArgument)
+				if err != nil {
+					return fmt.Errorf("%s: %w", c.operation, err)
+				}
+			}
+
+			return nil
+		},
+	},
+}
+
+// Run runs the conformance checks against the driver behind replication.
+func Run(replication grpcClient.VolumeReplication, cfg Config) *Report {
+	s := &suite{cfg: cfg, replication: replication}
+	report := &Report{}
+	start := time.Now()
+
+	s.runLifecycle(report)
+
+	for _, c := range contracts {
+		report.Results = append(report.Results, s.run(c))
+	}
+
+	report.Duration = time.Since(start)
+
+	return report
+}
+
+// runLifecycle runs the lifecycle checks, skipping the ones following a failed
+// check. The replication is disabled once they ran, whatever their outcome, so
+// that a failed run does not leave it enabled on the volume.
+func (s *suite) runLifecycle(report *Report) {
+	defer func() {
+		if result := s.run(cleanup); result.Failure != "" {
+			report.Results = append(report.Results, result)
+		}
+	}()
+
+	var failed string
+
+	for _, c := range lifecycle {
+		if failed != "" {
+			report.Results = append(report.Results, Result{
+				Name:    c.name,
+				Skipped: fmt.Sprintf("depends on the failed check %q", failed),
+			})
+
+			continue
+		}
+
+		result := s.run(c)
+		if result.Failure != "" {
+			failed = c.name
+		}
+
+		report.Results = append(report.Results, result)
+	}
+}
+
+// run runs a single check.
+func (s *suite) run(c check) Result {
+	start := time.Now()
+	err := c.run(s)
+
+	result := Result{Name: c.name, Duration: time.Since(start)}
+	if err != nil {
+		result.Failure = err.Error()
+	}
+
+	return result
+}
+
+// waitForResync polls ResyncVolume until the volume is ready.
+func (s *suite) waitForResync() error {
+	polls := max(s.cfg.ResyncPolls, 1)
+
+	for i := range polls {
+		resp, err := s.replication.ResyncVolume(s.cfg.ReplicationSource, s.cfg.ReplicationID, false, s.cfg.Secrets, s.cfg.Parameters)
+		if err != nil {
+			return err
+		}
+
+		if resp.GetReady() {
+			return nil
+		}
+
+		if i < polls-1 {
+			time.Sleep(s.cfg.ResyncInterval)
+		}
+	}
+
+	return fmt.Errorf("volume is not ready after %d ResyncVolume calls", polls)
+}
+
+// expectCode returns an error if the gRPC code of err is not one of the
+// expected codes.
+func expectCode(err error, expected ...codes.Code) error {
+	code := status.Code(err)
+	if slices.Contains(expected, code) {
+		return nil
+	}
+
+	if err == nil {
+		return fmt.Errorf("expected one of %v, got success", expected)
+	}
+
+	return fmt.Errorf("expected one of %v, got %s: %w", expected, code, err)
+}
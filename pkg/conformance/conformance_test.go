@@ -0,0 +1,127 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conformance
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/csi-addons/volume-replication-operator/pkg/client/fake"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	driver := fake.NewStatefulReplicationClient(2)
+	report := Run(driver, Config{ReplicationSource: fake.VolumeSource("volume-id"), ResyncPolls: 1})
+
+	require.False(t, report.Failed(), "%+v", report.Results)
+	require.Len(t, report.Results, len(lifecycle)+len(contracts))
+
+	for _, result := range report.Results {
+		require.Empty(t, result.Skipped, result.Name)
+	}
+}
+
+func TestRunFailure(t *testing.T) {
+	t.Parallel()
+
+	driver := fake.NewStatefulReplicationClient(1)
+	// a failed enable skips the rest of the lifecycle
+	driver.InjectFault(fake.Fault{Operation: fake.EnableVolumeReplication, Code: codes.Internal, Times: 1})
+
+	report := Run(driver, Config{ReplicationSource: fake.VolumeSource("volume-id")})
+	require.True(t, report.Failed())
+
+	require.NotEmpty(t, report.Results[0].Failure)
+
+	for _, result := range report.Results[1:len(lifecycle)] {
+		require.NotEmpty(t, result.Skipped, result.Name)
+	}
+
+	// the contracts do not depend on the lifecycle
+	require.Empty(t, report.Results[len(lifecycle)].Failure)
+}
+
+func TestRunCleanup(t *testing.T) {
+	t.Parallel()
+
+	source := fake.VolumeSource("volume-id")
+
+	driver := fake.NewStatefulReplicationClient(1)
+	// a failed demotion leaves the replication enabled
+	driver.InjectFault(fake.Fault{Operation: fake.DemoteVolume, Code: codes.Internal, Times: 1})
+
+	report := Run(driver, Config{ReplicationSource: source})
+	require.True(t, report.Failed())
+	require.Len(t, report.Results, len(lifecycle)+len(contracts))
+
+	_, enabled := driver.State(source)
+	require.False(t, enabled)
+
+	// a failed cleanup is reported
+	driver = fake.NewStatefulReplicationClient(1)
+	driver.InjectFault(fake.Fault{Operation: fake.DemoteVolume, Code: codes.Internal, Times: 1})
+	driver.InjectFault(fake.Fault{Operation: fake.DisableVolumeReplication, Source: source, Code: codes.Internal})
+
+	report = Run(driver, Config{ReplicationSource: source})
+	require.Len(t, report.Results, len(lifecycle)+1+len(contracts))
+	require.Equal(t, cleanup.name, report.Results[len(lifecycle)].Name)
+	require.NotEmpty(t, report.Results[len(lifecycle)].Failure)
+
+	_, enabled = driver.State(source)
+	require.True(t, enabled)
+}
+
+func TestExpectCode(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, expectCode(nil, codes.OK, codes.NotFound))
+	require.Error(t, expectCode(nil, codes.NotFound))
+}
+
+func TestWriteJUnit(t *testing.T) {
+	t.Parallel()
+
+	report := &Report{
+		Results: []Result{
+			{Name: "passed"},
+			{Name: "failed", Failure: "expected one of [NotFound], got success"},
+			{Name: "skipped", Skipped: "depends on the failed check"},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, report.WriteJUnit(buf, "suite"))
+
+	suites := junitTestSuites{}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &suites))
+	require.Len(t, suites.Suites, 1)
+
+	suite := suites.Suites[0]
+	require.Equal(t, "suite", suite.Name)
+	require.Equal(t, 3, suite.Tests)
+	require.Equal(t, 1, suite.Failures)
+	require.Equal(t, 1, suite.Skipped)
+	require.Nil(t, suite.Cases[0].Failure)
+	require.Equal(t, "expected one of [NotFound], got success", suite.Cases[1].Failure.Message)
+	require.Equal(t, "depends on the failed check", suite.Cases[2].Skipped.Message)
+}
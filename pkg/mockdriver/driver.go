@@ -0,0 +1,412 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package mockdriver implements a CSI-Addons replication driver without any
+// storage backend, to test the operator end-to-end. The replication state of
+// the volumes is kept in memory, and optionally in a JSON file.
+package mockdriver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/csi-addons/volume-replication-operator/pkg/client/fake"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	replicationlib "github.com/csi-addons/spec/lib/go/replication"
+	"github.com/go-logr/logr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// Options configure the mock driver.
+type Options struct {
+	// Name is the driver name returned by GetPluginInfo.
+	Name string
+	// Version is the driver version returned by GetPluginInfo.
+	Version string
+	// StateFile is the JSON file the replication state is kept in. The state
+	// is only kept in memory if it is empty.
+	StateFile string
+	// Latency is added to every replication RPC.
+	Latency time.Duration
+	// ReadyAfter is the number of ResyncVolume calls after which a resync
+	// completes.
+	ReadyAfter int
+	// SplitBrain are the volume and volume group ids that end up in split
+	// brain when demoted from primary. A split brain volume is only promoted
+	// with force, and is only ready again after a forced resync.
+	SplitBrain []string
+	// Faults are injected into the replication RPCs.
+	Faults []fake.Fault
+	// Log is the logger of the driver.
+	Log logr.Logger
+}
+
+// Driver serves the CSI-Addons replication Controller service and the CSI
+// Identity service.
+type Driver struct {
+	replicationlib.UnimplementedControllerServer
+	csi.UnimplementedIdentityServer
+
+	opts       Options
+	volumes    *fake.StatefulReplicationClient
+	splitBrain map[string]bool
+	// mu serializes the state changes, so that they are saved in order.
+	mu sync.Mutex
+}
+
+// New returns a Driver with the state loaded from the state file, if any.
+func New(opts Options) (*Driver, error) {
+	d := &Driver{
+		opts:       opts,
+		volumes:    fake.NewStatefulReplicationClient(opts.ReadyAfter),
+		splitBrain: make(map[string]bool, len(opts.SplitBrain)),
+	}
+
+	for _, id := range opts.SplitBrain {
+		d.splitBrain[id] = true
+	}
+
+	for _, fault := range opts.Faults {
+		d.volumes.InjectFault(fault)
+	}
+
+	if opts.StateFile != "" {
+		data, err := os.ReadFile(opts.StateFile)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read state file %q: %w", opts.StateFile, err)
+		}
+
+		if len(data) > 0 {
+			states := map[string]fake.VolumeState{}
+
+			err = json.Unmarshal(data, &states)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse state file %q: %w", opts.StateFile, err)
+			}
+
+			d.volumes.SetStates(states)
+		}
+	}
+
+	return d, nil
+}
+
+// Serve serves the driver on the unix socket of the endpoint, e.g.
+// "unix:///csi/csi.sock", until the context is done.
+func (d *Driver) Serve(ctx context.Context, endpoint string) error {
+	path, ok := strings.CutPrefix(endpoint, "unix://")
+	if !ok {
+		return fmt.Errorf("endpoint %q is not a unix socket", endpoint)
+	}
+
+	// remove the socket left behind by a previous run
+	err := os.Remove(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove socket %q: %w", path, err)
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %q: %w", path, err)
+	}
+
+	server := grpc.NewServer(grpc.UnaryInterceptor(d.logCalls))
+	replicationlib.RegisterControllerServer(server, d)
+	csi.RegisterIdentityServer(server, d)
+
+	go func() {
+		<-ctx.Done()
+		server.GracefulStop()
+	}()
+
+	d.opts.Log.Info("serving mock replication driver", "Endpoint", endpoint, "Name", d.opts.Name)
+
+	return server.Serve(listener)
+}
+
+// logCalls logs the RPCs and their result.
+func (d *Driver) logCalls(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		d.opts.Log.Info("RPC failed", "Method", info.FullMethod, "Code", status.Code(err).String(), "Error", err.Error())
+	} else {
+		d.opts.Log.V(1).Info("RPC succeeded", "Method", info.FullMethod)
+	}
+
+	return resp, err
+}
+
+// delay waits for the configured latency.
+func (d *Driver) delay(ctx context.Context) error {
+	if d.opts.Latency <= 0 {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	case <-time.After(d.opts.Latency):
+		return nil
+	}
+}
+
+// update runs the replication operation and saves the resulting state.
+func (d *Driver) update(ctx context.Context, op func() error) error {
+	err := d.delay(ctx)
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	err = op()
+	if err != nil {
+		return err
+	}
+
+	return d.save()
+}
+
+// save writes the replication state to the state file. It must be called
+// with the lock held.
+func (d *Driver) save() error {
+	if d.opts.StateFile == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(d.volumes.States(), "", "  ")
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to encode state: %v", err)
+	}
+
+	// write and rename, so that the state file is never partially written
+	tmp, err := os.CreateTemp(filepath.Dir(d.opts.StateFile), filepath.Base(d.opts.StateFile)+".*")
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to save state: %v", err)
+	}
+
+	_, err = tmp.Write(data)
+	if cErr := tmp.Close(); err == nil {
+		err = cErr
+	}
+
+	if err == nil {
+		err = os.Rename(tmp.Name(), d.opts.StateFile)
+	}
+
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+
+		return status.Errorf(codes.Internal, "failed to save state: %v", err)
+	}
+
+	return nil
+}
+
+// sourceID returns the volume or volume group id of the replication source.
+func sourceID(replicationSource *replicationlib.ReplicationSource) string {
+	if id := replicationSource.GetVolume().GetVolumeId(); id != "" {
+		return id
+	}
+
+	return replicationSource.GetVolumegroup().GetVolumeGroupId()
+}
+
+// GetPluginInfo returns the name and version of the driver.
+func (d *Driver) GetPluginInfo(context.Context, *csi.GetPluginInfoRequest) (*csi.GetPluginInfoResponse, error) {
+	return &csi.GetPluginInfoResponse{Name: d.opts.Name, VendorVersion: d.opts.Version}, nil
+}
+
+// GetPluginCapabilities returns no capabilities, the driver only serves
+// the replication service.
+func (d *Driver) GetPluginCapabilities(context.Context, *csi.GetPluginCapabilitiesRequest) (*csi.GetPluginCapabilitiesResponse, error) {
+	return &csi.GetPluginCapabilitiesResponse{}, nil
+}
+
+// Probe reports the driver as ready.
+func (d *Driver) Probe(context.Context, *csi.ProbeRequest) (*csi.ProbeResponse, error) {
+	return &csi.ProbeResponse{Ready: wrapperspb.Bool(true)}, nil
+}
+
+// EnableVolumeReplication enables the replication of the source as primary.
+func (d *Driver) EnableVolumeReplication(ctx context.Context, req *replicationlib.EnableVolumeReplicationRequest,
+) (*replicationlib.EnableVolumeReplicationResponse, error) {
+	var resp *replicationlib.EnableVolumeReplicationResponse
+
+	err := d.update(ctx, func() (err error) {
+		resp, err = d.volumes.EnableVolumeReplication(req.GetReplicationSource(), req.GetReplicationId(),
+			req.GetSecrets(), req.GetParameters())
+
+		return err
+	})
+
+	return resp, err
+}
+
+// DisableVolumeReplication disables the replication of the source.
+func (d *Driver) DisableVolumeReplication(ctx context.Context, req *replicationlib.DisableVolumeReplicationRequest,
+) (*replicationlib.DisableVolumeReplicationResponse, error) {
+	var resp *replicationlib.DisableVolumeReplicationResponse
+
+	err := d.update(ctx, func() (err error) {
+		resp, err = d.volumes.DisableVolumeReplication(req.GetReplicationSource(), req.GetReplicationId(),
+			req.GetSecrets(), req.GetParameters())
+
+		return err
+	})
+
+	return resp, err
+}
+
+// PromoteVolume promotes the source to primary.
+func (d *Driver) PromoteVolume(ctx context.Context, req *replicationlib.PromoteVolumeRequest,
+) (*replicationlib.PromoteVolumeResponse, error) {
+	var resp *replicationlib.PromoteVolumeResponse
+
+	err := d.update(ctx, func() (err error) {
+		resp, err = d.volumes.PromoteVolume(req.GetReplicationSource(), req.GetReplicationId(), req.GetForce(),
+			req.GetSecrets(), req.GetParameters())
+
+		return err
+	})
+
+	return resp, err
+}
+
+// DemoteVolume demotes the source to secondary. Sources configured for
+// split brain end up in split brain when demoted from primary.
+func (d *Driver) DemoteVolume(ctx context.Context, req *replicationlib.DemoteVolumeRequest,
+) (*replicationlib.DemoteVolumeResponse, error) {
+	var resp *replicationlib.DemoteVolumeResponse
+
+	err := d.update(ctx, func() (err error) {
+		source := req.GetReplicationSource()
+		previous, _ := d.volumes.State(source)
+
+		resp, err = d.volumes.DemoteVolume(source, req.GetReplicationId(), req.GetSecrets(), req.GetParameters())
+		if err != nil {
+			return err
+		}
+
+		if previous.Role == fake.Primary && d.splitBrain[sourceID(source)] {
+			state, _ := d.volumes.State(source)
+			state.SplitBrain = true
+			d.volumes.SetState(source, state)
+		}
+
+		return nil
+	})
+
+	return resp, err
+}
+
+// ResyncVolume polls the resync of the source.
+func (d *Driver) ResyncVolume(ctx context.Context, req *replicationlib.ResyncVolumeRequest,
+) (*replicationlib.ResyncVolumeResponse, error) {
+	var resp *replicationlib.ResyncVolumeResponse
+
+	err := d.update(ctx, func() (err error) {
+		resp, err = d.volumes.ResyncVolume(req.GetReplicationSource(), req.GetReplicationId(), req.GetForce(),
+			req.GetSecrets(), req.GetParameters())
+
+		return err
+	})
+
+	return resp, err
+}
+
+// GetVolumeReplicationInfo reports the current time as the last sync time
+// of an enabled source.
+func (d *Driver) GetVolumeReplicationInfo(ctx context.Context, req *replicationlib.GetVolumeReplicationInfoRequest,
+) (*replicationlib.GetVolumeReplicationInfoResponse, error) {
+	err := d.delay(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, ok := d.volumes.State(req.GetReplicationSource())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "replication is not enabled for %q", sourceID(req.GetReplicationSource()))
+	}
+
+	return &replicationlib.GetVolumeReplicationInfoResponse{LastSyncTime: timestamppb.Now()}, nil
+}
+
+// ParseFault parses a fault given as "<operation>:<code>[:<times>]", e.g.
+// "PromoteVolume:FailedPrecondition:1".
+func ParseFault(value string) (fake.Fault, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return fake.Fault{}, fmt.Errorf("fault %q is not in the <operation>:<code>[:<times>] format", value)
+	}
+
+	fault := fake.Fault{
+		Operation: fake.Operation(parts[0]),
+		Message:   "injected fault",
+	}
+
+	switch fault.Operation {
+	case fake.EnableVolumeReplication, fake.DisableVolumeReplication, fake.PromoteVolume,
+		fake.DemoteVolume, fake.ResyncVolume:
+	default:
+		return fake.Fault{}, fmt.Errorf("unknown operation %q in fault %q", parts[0], value)
+	}
+
+	code, ok := parseCode(parts[1])
+	if !ok {
+		return fake.Fault{}, fmt.Errorf("unknown code %q in fault %q", parts[1], value)
+	}
+
+	fault.Code = code
+
+	if len(parts) == 3 {
+		times, err := strconv.Atoi(parts[2])
+		if err != nil || times < 0 {
+			return fake.Fault{}, fmt.Errorf("invalid times %q in fault %q", parts[2], value)
+		}
+
+		fault.Times = times
+	}
+
+	return fault, nil
+}
+
+// parseCode returns the gRPC error code of the given name, e.g.
+// "FailedPrecondition".
+func parseCode(name string) (codes.Code, bool) {
+	for code := codes.Canceled; code <= codes.Unauthenticated; code++ {
+		if strings.EqualFold(code.String(), name) {
+			return code, true
+		}
+	}
+
+	return codes.OK, false
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockdriver
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	grpcClient "github.com/csi-addons/volume-replication-operator/pkg/client"
+	"github.com/csi-addons/volume-replication-operator/pkg/client/fake"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// startDriver serves a driver on a temporary socket and returns a client
+// connected to it.
+func startDriver(t *testing.T, opts Options) *grpcClient.Client {
+	t.Helper()
+
+	// unix socket paths are limited in length, t.TempDir can be too long
+	dir, err := os.MkdirTemp("", "mockdriver")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	opts.Log = logr.Discard()
+	driver, err := New(opts)
+	require.NoError(t, err)
+
+	endpoint := "unix://" + filepath.Join(dir, "csi.sock")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+
+	go func() {
+		done <- driver.Serve(ctx, endpoint)
+	}()
+
+	client, err := grpcClient.New(endpoint, 5*time.Second)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		// close the connection first, the client exits on connection loss
+		_ = client.Client.Close()
+		cancel()
+		require.NoError(t, <-done)
+	})
+
+	require.NoError(t, client.Probe())
+
+	return client
+}
+
+func TestDriverLifecycle(t *testing.T) {
+	t.Parallel()
+
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	client := startDriver(t, Options{Name: "mock.driver", StateFile: stateFile, ReadyAfter: 2})
+
+	name, err := client.GetDriverName()
+	require.NoError(t, err)
+	require.Equal(t, "mock.driver", name)
+
+	replication := grpcClient.NewReplicationClient(client.Client, 5*time.Second)
+	source := fake.VolumeSource("volume-id")
+
+	_, err = replication.EnableVolumeReplication(source, "", nil, nil)
+	require.NoError(t, err)
+
+	_, err = replication.DemoteVolume(source, "", nil, nil)
+	require.NoError(t, err)
+
+	resp, err := replication.ResyncVolume(source, "", true, nil, nil)
+	require.NoError(t, err)
+	require.False(t, resp.GetReady())
+
+	// the state is kept across restarts
+	restarted, err := New(Options{StateFile: stateFile, ReadyAfter: 2, Log: logr.Discard()})
+	require.NoError(t, err)
+
+	state, ok := restarted.volumes.State(source)
+	require.True(t, ok)
+	require.Equal(t, fake.VolumeState{Role: fake.Secondary, Resyncing: true, Polls: 1}, state)
+
+	resp, err = replication.ResyncVolume(source, "", false, nil, nil)
+	require.NoError(t, err)
+	require.True(t, resp.GetReady())
+
+	_, err = replication.DisableVolumeReplication(source, "", nil, nil)
+	require.NoError(t, err)
+
+	_, err = replication.DisableVolumeReplication(source, "", nil, nil)
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestDriverSplitBrainAndFaults(t *testing.T) {
+	t.Parallel()
+
+	client := startDriver(t, Options{
+		ReadyAfter: 1,
+		SplitBrain: []string{"volume-id"},
+		Faults:     []fake.Fault{{Operation: fake.EnableVolumeReplication, Code: codes.Unavailable, Times: 1}},
+	})
+
+	replication := grpcClient.NewReplicationClient(client.Client, 5*time.Second)
+	source := fake.VolumeSource("volume-id")
+
+	_, err := replication.EnableVolumeReplication(source, "", nil, nil)
+	require.Equal(t, codes.Unavailable, status.Code(err))
+
+	_, err = replication.EnableVolumeReplication(source, "", nil, nil)
+	require.NoError(t, err)
+
+	_, err = replication.DemoteVolume(source, "", nil, nil)
+	require.NoError(t, err)
+
+	// a split brain volume is not ready until resynced with force
+	resp, err := replication.ResyncVolume(source, "", false, nil, nil)
+	require.NoError(t, err)
+	require.False(t, resp.GetReady())
+
+	_, err = replication.PromoteVolume(source, "", false, nil, nil)
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	resp, err = replication.ResyncVolume(source, "", true, nil, nil)
+	require.NoError(t, err)
+	require.True(t, resp.GetReady())
+
+	_, err = replication.PromoteVolume(source, "", false, nil, nil)
+	require.NoError(t, err)
+}
+
+func TestParseFault(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		value     string
+		fault     fake.Fault
+		expectErr bool
+	}{
+		{
+			value: "PromoteVolume:FailedPrecondition",
+			fault: fake.Fault{Operation: fake.PromoteVolume, Code: codes.FailedPrecondition, Message: "injected fault"},
+		},
+		{
+			value: "ResyncVolume:unavailable:3",
+			fault: fake.Fault{Operation: fake.ResyncVolume, Code: codes.Unavailable, Message: "injected fault", Times: 3},
+		},
+		{value: "PromoteVolume", expectErr: true},
+		{value: "GetVolume:Internal", expectErr: true},
+		{value: "PromoteVolume:OK", expectErr: true},
+		{value: "PromoteVolume:Internal:-1", expectErr: true},
+	}
+
+	for _, tc := range testcases {
+		fault, err := ParseFault(tc.value)
+		if tc.expectErr {
+			require.Error(t, err, tc.value)
+
+			continue
+		}
+
+		require.NoError(t, err, tc.value)
+		require.Equal(t, tc.fault, fault)
+	}
+}